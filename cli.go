@@ -23,6 +23,7 @@ import (
 type cliArgs struct {
 	Version  VersionCommand  `cmd:"" help:"Print version and exit."`
 	Generate GenerateCommand `cmd:"" help:"Generate conversions."`
+	Run      RunCommand      `cmd:"" help:"Generate conversions of all pairs described in the configuration file."`
 
 	InstallCompletions kongplete.InstallCompletions `cmd:"" help:"Install shell completions."`
 }
@@ -40,6 +41,13 @@ type structPath struct {
 
 // UnmarshalText unmarshal for struct arguments
 func (p *structPath) UnmarshalText(x []byte) error {
+	if !p.needLocal && strings.HasPrefix(string(x), "map[") {
+		// литерал словаря со строковыми ключами вместо secondary-структуры
+		p.pkgPath = ""
+		p.name = string(x)
+		return nil
+	}
+
 	parts := strings.Split(string(x), ":")
 	if len(parts) != 2 {
 		return errors.Newf("<pkg-path>:<struct name> value required, got '%s'", string(x))
@@ -48,16 +56,6 @@ func (p *structPath) UnmarshalText(x []byte) error {
 	p.pkgPath = parts[0]
 	p.name = parts[1]
 
-	if p.needLocal {
-		// проверка, что задан локальный пакет (относительным путём)
-		if !strings.HasPrefix(p.pkgPath, "./") {
-			return errors.Newf(
-				"pkg-path must be set with relative path against current project — must be in the project root — got '%s'",
-				p.pkgPath,
-			)
-		}
-	}
-
 	return nil
 }
 
@@ -5,58 +5,153 @@ import (
 	"strings"
 
 	"github.com/sirkon/errors"
-	"github.com/sirkon/gogh"
 	"github.com/sirkon/jsonexec"
 	"github.com/sirkon/metamorph/internal/generator"
-	"github.com/sirkon/metamorph/internal/imports"
 )
 
 // GenerateCommand generation command
 type GenerateCommand struct {
-	Primary          structPath  `arg:"" help:"Primary structure to generate conversions in its package. Must look like <rel-path>:<name>." predictor:"local-struct-path"`
-	Secondary        structPath  `arg:"" help:"Secondary structure to generate conversions to and from the primary one. Must look like <pkg-path>:<name>." predictor:"free-struct-path"`
+	Primary          structPath  `arg:"" help:"Primary structure to generate conversions in its package. Must look like <rel-path>:<name>, or <pkg-path>:<name> if --out-pkg is set." predictor:"local-struct-path"`
+	Secondary        structPath  `arg:"" help:"Secondary structure to generate conversions to and from the primary one. Must look like <pkg-path>:<name>. A string keyed map literal like map[string]any is accepted as well." predictor:"free-struct-path"`
 	PrimaryMethod    string      `short:"m" help:"MethodPrimary name for the primary -> secondary conversion. Free function will be generated instead if not set."`
 	ExcludeFields    []string    `short:"x" help:"Exclude these fields from automatic conversion generation."`
 	StructuredErrors packagePath `short:"e" help:"Path to structured errors package." predictor:"outer-package"`
+	ProtoNames       bool        `help:"Match fields of protoc-gen-go structures by proto names and json_name instead of Go identifiers and skip deprecated ones."`
+	TagKey           string      `short:"t" help:"Match fields by values of struct tags with this key (json, db, yaml, etc) before matching them by names."`
+	Strconv          bool        `help:"Match strings with numbers and booleans for all fields, convert them with strconv."`
+	StrconvFields    []string    `name:"strconv-field" help:"Match strings with numbers and booleans for these primary fields only, convert them with strconv."`
+	Time             string      `help:"Represent time.Time and time.Duration as integers (unix, unix-ms, unix-ns) or strings of the given layout (rfc3339, 2006-01-02, etc) for all fields."`
+	TimeFields       []string    `name:"time-field" help:"Represent time.Time and time.Duration of the primary field with the given policy. Must look like <field>=<policy>."`
+	SQLFields        []string    `name:"sql-field" help:"Match driver.Valuer and sql.Scanner implementations of these primary fields with any primitive of driver.Value, not only with the one they are built on."`
+	AlwaysErrors     bool        `help:"Always generate conversions returning (*T, error), even if they cannot fail, to keep API stable."`
+	ByValue          bool        `help:"Generate conversions that cannot fail as func(X) Y instead of func(*X) *Y."`
+	Batch            bool        `help:"Generate XSliceToYSlice and XMapToYMap helpers converting slices and maps next to each conversion."`
+	Tests            bool        `help:"Generate round trip fuzz tests of matched fields into <file>_metamorph_test.go."`
+	OutPkg           string      `name:"out-pkg" help:"Put conversions into this package of the current module instead of the package of the primary structure. Must look like ./<rel-path>."`
+	Map              []string    `name:"map" help:"Match the primary field with the secondary one regardless of their names and tags. Must look like <primary field>=<secondary field>."`
+	Direction        string      `enum:"prim-to-sec,sec-to-prim,both" default:"both" help:"Generate conversions in this direction only (prim-to-sec, sec-to-prim or both). Fields mismatches and user defined conversions are only required for it."`
+
+	outputFlags `embed:""`
 }
 
 // Run запуск генерации
 func (c *GenerateCommand) Run(rctx *RunContext) error {
-	var listInfo struct {
-		Dir  string
-		Path string
+	mod, err := currentModule()
+	if err != nil {
+		return err
+	}
+
+	g, err := c.generator(mod.Path)
+	if err != nil {
+		return err
+	}
+
+	return outputGenerated(c.outputMode(), mod.Dir, []generation{
+		{
+			g:          g,
+			errorsPath: c.StructuredErrors.path,
+		},
+	})
+}
+
+// moduleInfo сведения о текущем модуле: корень и путь
+type moduleInfo struct {
+	Dir  string
+	Path string
+}
+
+// currentModule получение сведений о текущем модуле
+func currentModule() (moduleInfo, error) {
+	var res moduleInfo
+	if err := jsonexec.Run(&res, "go", "list", "-m", "--json"); err != nil {
+		return res, errors.Wrap(err, "retrieve current module information")
+	}
+
+	return res, nil
+}
+
+// generator создание генератора конвертаций согласно параметрам команды в модуле modPath, opts дополняют опции
+// заданные ими
+func (c *GenerateCommand) generator(modPath string, opts ...generator.Option) (*generator.Generator, error) {
+	// проверка, что задан локальный пакет (относительным путём), если код пишется в пакет primary-структуры
+	if c.OutPkg == "" && !strings.HasPrefix(c.Primary.pkgPath, "./") {
+		return nil, errors.Newf(
+			"pkg-path must be set with relative path against current project — must be in the project root — unless --out-pkg is set, got '%s'",
+			c.Primary.pkgPath,
+		)
+	}
+
+	if c.ProtoNames {
+		opts = append(opts, generator.WithProtoNames())
+	}
+	if c.TagKey != "" {
+		opts = append(opts, generator.WithTagKey(c.TagKey))
+	}
+	if c.Strconv {
+		opts = append(opts, generator.WithStrconv())
+	}
+	if len(c.StrconvFields) > 0 {
+		opts = append(opts, generator.WithStrconv(c.StrconvFields...))
 	}
-	if err := jsonexec.Run(&listInfo, "go", "list", "-m", "--json"); err != nil {
-		return errors.Wrap(err, "retrieve current module information")
+	if c.Time != "" {
+		opts = append(opts, generator.WithTimePolicy(c.Time))
 	}
+	for _, tf := range c.TimeFields {
+		field, policy, ok := strings.Cut(tf, "=")
+		if !ok || field == "" || policy == "" {
+			return nil, errors.Newf("<field>=<policy> value required for time field, got '%s'", tf)
+		}
+
+		opts = append(opts, generator.WithTimePolicy(policy, field))
+	}
+	if len(c.SQLFields) > 0 {
+		opts = append(opts, generator.WithSQLFields(c.SQLFields...))
+	}
+	for _, m := range c.Map {
+		prim, sec, ok := strings.Cut(m, "=")
+		if !ok || prim == "" || sec == "" {
+			return nil, errors.Newf("<primary field>=<secondary field> value required for fields mapping, got '%s'", m)
+		}
+
+		opts = append(opts, generator.WithFieldMapping(prim, sec))
+	}
+
+	if c.AlwaysErrors {
+		opts = append(opts, generator.WithAlwaysErrors())
+	}
+	if c.ByValue {
+		opts = append(opts, generator.WithByValue())
+	}
+	if c.Batch {
+		opts = append(opts, generator.WithBatchHelpers())
+	}
+	if c.Tests {
+		opts = append(opts, generator.WithTests())
+	}
+	if c.OutPkg != "" {
+		if !strings.HasPrefix(c.OutPkg, "./") {
+			return nil, errors.Newf("output package must be set with relative path against current project, got '%s'", c.OutPkg)
+		}
+
+		opts = append(opts, generator.WithOutputPackage(undottedPrefix(strings.TrimRight(c.OutPkg, "/"), modPath)))
+	}
+	opts = append(opts, generator.WithDirection(generator.Direction(c.Direction)))
 
 	g, err := generator.New(
-		undottedPrefix(c.Primary.pkgPath, listInfo.Path),
+		undottedPrefix(c.Primary.pkgPath, modPath),
 		c.Primary.name,
-		undottedPrefix(c.Secondary.pkgPath, listInfo.Path),
+		undottedPrefix(c.Secondary.pkgPath, modPath),
 		c.Secondary.name,
 		c.PrimaryMethod,
 		c.StructuredErrors.path != "",
 		c.ExcludeFields,
+		opts...,
 	)
 	if err != nil {
-		return errors.Wrap(err, "setup generator")
-	}
-
-	prj, err := gogh.New[*imports.Imports](gogh.FancyFmt, imports.New(c.StructuredErrors.path))
-	if err != nil {
-		return errors.Wrap(err, "setup matiss for the current project")
-	}
-
-	if err := g.Generate(prj); err != nil {
-		return errors.Wrap(err, "generate source code")
-	}
-
-	if err := prj.Render(); err != nil {
-		return errors.Wrap(err, "render generated source code")
+		return nil, errors.Wrap(err, "setup generator")
 	}
 
-	return nil
+	return g, nil
 }
 
 func undottedPrefix(pkg, modPkg string) string {
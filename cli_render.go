@@ -0,0 +1,272 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/message"
+	"github.com/sirkon/metamorph/internal/app"
+	"github.com/sirkon/metamorph/internal/diff"
+	"github.com/sirkon/metamorph/internal/generator"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// generation генерация конвертаций одной пары структур
+type generation struct {
+	g *generator.Generator
+	// errorsPath путь пакета структурированных ошибок, пустой если используются ошибки стандартной библиотеки
+	errorsPath string
+}
+
+// outputFlags флаги способа вывода сгенерированного кода
+type outputFlags struct {
+	Check  bool `xor:"output" help:"Do not write generated code, print a unified diff against files on disk and fail if any of them is out of date."`
+	DryRun bool `name:"dry-run" xor:"output" help:"Do not write generated code, print generated files headed with their names instead."`
+	Stdout bool `xor:"output" help:"The same as --dry-run."`
+	Diff   bool `xor:"output" help:"Do not write generated code, print a unified diff against files on disk instead."`
+}
+
+// outputMode способ вывода сгенерированного кода согласно флагам
+func (f *outputFlags) outputMode() outputMode {
+	switch {
+	case f.Check:
+		return outputCheck
+	case f.DryRun, f.Stdout:
+		return outputPrint
+	case f.Diff:
+		return outputDiff
+	default:
+		return outputRender
+	}
+}
+
+// outputMode способ вывода сгенерированного кода
+type outputMode int
+
+const (
+	// outputRender запись в файлы модуля
+	outputRender outputMode = iota
+	// outputCheck вывод различий с файлами на диске и ошибка, если они есть
+	outputCheck
+	// outputPrint вывод сгенерированных файлов с заголовками из их имён
+	outputPrint
+	// outputDiff вывод различий с файлами на диске
+	outputDiff
+)
+
+// renderedFile сгенерированный файл: путь относительно корня модуля, содержимое на диске (nil, если файла нет)
+// и содержимое после генерации
+type renderedFile struct {
+	path string
+	old  []byte
+	new  []byte
+}
+
+// outputGenerated генерация кода в модуль с корнем root и вывод результата согласно mode. Файлы
+// записываются только после того, как код всех пар был сгенерирован.
+func outputGenerated(mode outputMode, root string, gens []generation) error {
+	if mode == outputRender {
+		prjs, _, err := generateProjects("", gens)
+		if err != nil {
+			return err
+		}
+
+		for _, prj := range prjs {
+			if err := prj.Render(); err != nil {
+				return errors.Wrap(err, "render generated source code")
+			}
+		}
+
+		return nil
+	}
+
+	files, err := renderInMemory(root, gens)
+	if err != nil {
+		return err
+	}
+
+	if mode == outputPrint {
+		return printGenerated(files)
+	}
+
+	stale, err := diffGenerated(files)
+	if err != nil {
+		return err
+	}
+
+	if mode == outputCheck && stale > 0 {
+		return errors.Newf("%d of %d generated files are out of date", stale, len(files))
+	}
+
+	return nil
+}
+
+// generateProjects генерация кода каждой пары в отдельный проект текущего модуля, файлы которого пишутся в каталог
+// root, в корень модуля если он пуст. Возвращает проекты и пути файлов относительно корня, которые будут записаны
+// при их отрисовке.
+func generateProjects(root string, gens []generation) ([]*gogh.Module[*imports.Imports], []string, error) {
+	var prjs []*gogh.Module[*imports.Imports]
+	var files []string
+	owners := map[string]string{}
+	for _, gen := range gens {
+		prj, err := newProject(root, gen.errorsPath)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "setup matiss for the current project")
+		}
+
+		if err := gen.g.Generate(prj); err != nil {
+			return nil, nil, errors.Wrap(err, "generate source code")
+		}
+
+		// файлы разных пар не должны совпадать, иначе одна из конвертаций будет перетёрта другой
+		for _, file := range gen.g.Files() {
+			if owner, ok := owners[file]; ok {
+				return nil, nil, errors.Newf("conversions %s and %s are to be generated into the same file %s", owner, gen.g, file)
+			}
+			owners[file] = gen.g.String()
+		}
+
+		prjs = append(prjs, prj)
+		files = append(files, gen.g.Files()...)
+	}
+
+	return prjs, files, nil
+}
+
+// newProject создание проекта текущего модуля, файлы которого пишутся в каталог root, в корень модуля если он
+// пуст. gogh берёт корень проекта из go.mod текущего каталога, поэтому в непустом root должна лежать копия
+// go.mod модуля, и проект создаётся из этого каталога.
+func newProject(root, errorsPath string) (*gogh.Module[*imports.Imports], error) {
+	if root == "" {
+		return gogh.New[*imports.Imports](gogh.FancyFmt, imports.New(errorsPath))
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "get current directory")
+	}
+	if err := os.Chdir(root); err != nil {
+		return nil, errors.Wrap(err, "change directory to the temporary one")
+	}
+	defer func() {
+		if err := os.Chdir(wd); err != nil {
+			message.Warning(errors.Wrap(err, "return to the current directory"))
+		}
+	}()
+
+	return gogh.New[*imports.Imports](gogh.FancyFmt, imports.New(errorsPath))
+}
+
+// renderInMemory генерация кода без записи в файлы модуля с корнем root. gogh пишет отрисованный код сразу на
+// диск, поэтому файлы пишутся во временный каталог, куда переносятся go.mod модуля и только существующие файлы,
+// которые будут сгенерированы, чтобы опции отрисовки вроде gogh.Shy работали как обычно. Каталог удаляется
+// в любом случае.
+func renderInMemory(root string, gens []generation) ([]renderedFile, error) {
+	shadow, err := os.MkdirTemp("", app.Name+"-*")
+	if err != nil {
+		return nil, errors.Wrap(err, "create temporary directory for generated code")
+	}
+	defer func() {
+		if err := os.RemoveAll(shadow); err != nil {
+			message.Warning(errors.Wrap(err, "remove temporary directory of generated code"))
+		}
+	}()
+
+	gomod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, errors.Wrap(err, "read go.mod of the current module")
+	}
+	if err := os.WriteFile(filepath.Join(shadow, "go.mod"), gomod, 0644); err != nil {
+		return nil, errors.Wrap(err, "copy go.mod into temporary directory")
+	}
+
+	prjs, files, err := generateProjects(shadow, gens)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]renderedFile, 0, len(files))
+	for _, file := range files {
+		old, err := os.ReadFile(filepath.Join(root, file))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "read "+file)
+		}
+
+		if old != nil {
+			dst := filepath.Join(shadow, file)
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return nil, errors.Wrap(err, "create a directory for "+file+" in temporary directory")
+			}
+			if err := os.WriteFile(dst, old, 0644); err != nil {
+				return nil, errors.Wrap(err, "copy "+file+" into temporary directory")
+			}
+		}
+
+		res = append(res, renderedFile{
+			path: file,
+			old:  old,
+		})
+	}
+
+	for _, prj := range prjs {
+		if err := prj.Render(); err != nil {
+			return nil, errors.Wrap(err, "render generated source code")
+		}
+	}
+
+	for i, file := range res {
+		data, err := os.ReadFile(filepath.Join(shadow, file.path))
+		if err != nil {
+			return nil, errors.Wrap(err, "read rendered "+file.path)
+		}
+
+		res[i].new = data
+	}
+
+	return res, nil
+}
+
+// printGenerated вывод содержимого сгенерированных файлов, каждому предшествует заголовок с его именем
+func printGenerated(files []renderedFile) error {
+	for i, file := range files {
+		header := "==> " + file.path + " <==\n"
+		if i > 0 {
+			header = "\n" + header
+		}
+
+		if _, err := os.Stdout.WriteString(header); err != nil {
+			return errors.Wrap(err, "print header of "+file.path)
+		}
+		if _, err := os.Stdout.Write(file.new); err != nil {
+			return errors.Wrap(err, "print "+file.path)
+		}
+	}
+
+	return nil
+}
+
+// diffGenerated вывод различий сгенерированных файлов с файлами на диске в виде unified diff, возвращает
+// количество различающихся файлов
+func diffGenerated(files []renderedFile) (int, error) {
+	var stale int
+	for _, file := range files {
+		oldName := "a/" + file.path
+		if file.old == nil {
+			oldName = "/dev/null"
+		}
+
+		d := diff.Unified(oldName, "b/"+file.path, file.old, file.new)
+		if d == "" {
+			continue
+		}
+
+		if _, err := os.Stdout.WriteString(d); err != nil {
+			return 0, errors.Wrap(err, "print diff")
+		}
+		stale++
+	}
+
+	return stale, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/sirkon/errors"
+	"github.com/sirkon/metamorph/internal/generator"
+)
+
+// RunCommand команда генерации всех конвертаций, описанных в файле конфигурации
+type RunCommand struct {
+	Config string `short:"c" default:"metamorph.yaml" help:"Configuration file describing pairs of structures to generate conversions for."`
+
+	outputFlags `embed:""`
+}
+
+// Run запуск генерации
+func (c *RunCommand) Run(rctx *RunContext) error {
+	cfg, err := loadRunConfig(c.Config)
+	if err != nil {
+		return errors.Wrap(err, "load config")
+	}
+
+	cmds, err := cfg.commands()
+	if err != nil {
+		return errors.Wrap(err, "setup generation")
+	}
+
+	mod, err := currentModule()
+	if err != nil {
+		return err
+	}
+
+	// пакеты всех пар загружаются за один раз вместе с пакетами сгенерированного кода, где могут быть функции
+	// пользователя
+	var paths []string
+	seen := map[string]struct{}{}
+	for _, cmd := range cmds {
+		for _, pkg := range []string{cmd.Primary.pkgPath, cmd.Secondary.pkgPath, strings.TrimRight(cmd.OutPkg, "/")} {
+			if pkg == "" {
+				continue
+			}
+
+			pkg = undottedPrefix(pkg, mod.Path)
+			if _, ok := seen[pkg]; ok {
+				continue
+			}
+			seen[pkg] = struct{}{}
+			paths = append(paths, pkg)
+		}
+	}
+
+	pkgs, err := generator.LoadPackages(paths...)
+	if err != nil {
+		return errors.Wrap(err, "load packages")
+	}
+
+	var gens []generation
+	for i, cmd := range cmds {
+		g, err := cmd.generator(mod.Path, generator.WithPackages(pkgs))
+		if err != nil {
+			return errors.Wrapf(err, "setup pair #%d %s", i+1, cfg.Pairs[i].Primary)
+		}
+
+		gens = append(gens, generation{
+			g:          g,
+			errorsPath: cmd.StructuredErrors.path,
+		})
+	}
+
+	// конвертации пар, primary которых определены в одном файле, пишутся в файлы с именами primary-типов
+	outputs := map[string]int{}
+	for _, gen := range gens {
+		outputs[gen.g.OutputFile()]++
+	}
+	for i, cmd := range cmds {
+		if outputs[gens[i].g.OutputFile()] < 2 {
+			continue
+		}
+
+		g, err := cmd.generator(mod.Path, generator.WithPackages(pkgs), generator.WithTypedFileName())
+		if err != nil {
+			return errors.Wrapf(err, "setup pair #%d %s", i+1, cfg.Pairs[i].Primary)
+		}
+		gens[i].g = g
+	}
+
+	return outputGenerated(c.outputMode(), mod.Dir, gens)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"sort"
+
+	"github.com/sirkon/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// runConfig описание конвертаций в файле конфигурации
+type runConfig struct {
+	Defaults pairOptions  `yaml:"defaults"`
+	Pairs    []pairConfig `yaml:"pairs"`
+}
+
+// pairOptions опции генерации, которые задаются как по умолчанию для всех пар, так и для каждой из них. Опции
+// пары важнее опций по умолчанию.
+type pairOptions struct {
+	Errors       string `yaml:"errors"`
+	Output       string `yaml:"output"`
+	ProtoNames   *bool  `yaml:"proto-names"`
+	Tag          string `yaml:"tag"`
+	Strconv      *bool  `yaml:"strconv"`
+	Time         string `yaml:"time"`
+	AlwaysErrors *bool  `yaml:"always-errors"`
+	ByValue      *bool  `yaml:"by-value"`
+	Batch        *bool  `yaml:"batch"`
+	Tests        *bool  `yaml:"tests"`
+	Direction    string `yaml:"direction"`
+}
+
+// pairConfig описание конвертаций пары структур
+type pairConfig struct {
+	Primary       string            `yaml:"primary"`
+	Secondary     string            `yaml:"secondary"`
+	Method        string            `yaml:"method"`
+	Exclude       []string          `yaml:"exclude"`
+	Map           map[string]string `yaml:"map"`
+	StrconvFields []string          `yaml:"strconv-fields"`
+	TimeFields    map[string]string `yaml:"time-fields"`
+	SQLFields     []string          `yaml:"sql-fields"`
+
+	pairOptions `yaml:",inline"`
+}
+
+// loadRunConfig чтение файла конфигурации, неизвестные ключи считаются ошибкой
+func loadRunConfig(path string) (*runConfig, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open config file")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var res runConfig
+	dec := yaml.NewDecoder(file)
+	dec.KnownFields(true)
+	if err := dec.Decode(&res); err != nil {
+		return nil, errors.Wrap(err, "decode config file "+path)
+	}
+
+	if len(res.Pairs) == 0 {
+		return nil, errors.Newf("no pairs to generate conversions for in %s", path)
+	}
+
+	return &res, nil
+}
+
+// commands команды генерации для каждой пары с учётом опций по умолчанию
+func (c *runConfig) commands() ([]*GenerateCommand, error) {
+	// пакеты ошибок проверяются загрузкой, поэтому каждый из них проверяется один раз
+	errs := map[string]packagePath{}
+
+	var res []*GenerateCommand
+	for i, pair := range c.Pairs {
+		cmd, err := pair.command(c.Defaults, errs)
+		if err != nil {
+			return nil, errors.Wrapf(err, "setup pair #%d %s %s", i+1, pair.Primary, pair.Secondary)
+		}
+
+		res = append(res, cmd)
+	}
+
+	return res, nil
+}
+
+// command команда генерации конвертаций пары с опциями по умолчанию defaults
+func (p *pairConfig) command(defaults pairOptions, errs map[string]packagePath) (*GenerateCommand, error) {
+	opts := p.pairOptions.merge(defaults)
+
+	res := &GenerateCommand{
+		PrimaryMethod: p.Method,
+		ExcludeFields: p.Exclude,
+		ProtoNames:    opts.ProtoNames != nil && *opts.ProtoNames,
+		TagKey:        opts.Tag,
+		Strconv:       opts.Strconv != nil && *opts.Strconv,
+		StrconvFields: p.StrconvFields,
+		Time:          opts.Time,
+		SQLFields:     p.SQLFields,
+		AlwaysErrors:  opts.AlwaysErrors != nil && *opts.AlwaysErrors,
+		ByValue:       opts.ByValue != nil && *opts.ByValue,
+		Batch:         opts.Batch != nil && *opts.Batch,
+		Tests:         opts.Tests != nil && *opts.Tests,
+		OutPkg:        opts.Output,
+		Direction:     opts.Direction,
+	}
+
+	res.Primary.needLocal = true
+	if err := res.Primary.UnmarshalText([]byte(p.Primary)); err != nil {
+		return nil, errors.Wrap(err, "parse primary")
+	}
+	if err := res.Secondary.UnmarshalText([]byte(p.Secondary)); err != nil {
+		return nil, errors.Wrap(err, "parse secondary")
+	}
+
+	if opts.Errors != "" {
+		pkg, ok := errs[opts.Errors]
+		if !ok {
+			if err := pkg.UnmarshalText([]byte(opts.Errors)); err != nil {
+				return nil, errors.Wrap(err, "check errors package")
+			}
+			errs[opts.Errors] = pkg
+		}
+
+		res.StructuredErrors = pkg
+	}
+
+	for _, field := range sortedKeys(p.Map) {
+		res.Map = append(res.Map, field+"="+p.Map[field])
+	}
+	for _, field := range sortedKeys(p.TimeFields) {
+		res.TimeFields = append(res.TimeFields, field+"="+p.TimeFields[field])
+	}
+
+	return res, nil
+}
+
+// merge опции, в которых незаданные значения взяты из defaults
+func (o pairOptions) merge(defaults pairOptions) pairOptions {
+	if o.Errors == "" {
+		o.Errors = defaults.Errors
+	}
+	if o.Output == "" {
+		o.Output = defaults.Output
+	}
+	if o.ProtoNames == nil {
+		o.ProtoNames = defaults.ProtoNames
+	}
+	if o.Tag == "" {
+		o.Tag = defaults.Tag
+	}
+	if o.Strconv == nil {
+		o.Strconv = defaults.Strconv
+	}
+	if o.Time == "" {
+		o.Time = defaults.Time
+	}
+	if o.AlwaysErrors == nil {
+		o.AlwaysErrors = defaults.AlwaysErrors
+	}
+	if o.ByValue == nil {
+		o.ByValue = defaults.ByValue
+	}
+	if o.Batch == nil {
+		o.Batch = defaults.Batch
+	}
+	if o.Tests == nil {
+		o.Tests = defaults.Tests
+	}
+	if o.Direction == "" {
+		o.Direction = defaults.Direction
+	}
+
+	return o
+}
+
+// sortedKeys ключи словаря по возрастанию
+func sortedKeys(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+
+	return res
+}
@@ -11,6 +11,7 @@ require (
 	github.com/sirkon/message v1.5.1
 	github.com/willabides/kongplete v0.3.0
 	golang.org/x/tools v0.1.9
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
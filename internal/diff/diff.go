@@ -0,0 +1,216 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context количество неизменных строк вокруг изменений в каждом блоке
+const context = 3
+
+// Unified returns a unified diff of old and new contents of a file, an empty string if they are the same. Names
+// are put into the header, an empty content stands for a missing file.
+func Unified(oldName, newName string, oldText, newText []byte) string {
+	if string(oldText) == string(newText) {
+		return ""
+	}
+
+	ops := lineOps(splitLines(string(oldText)), splitLines(string(newText)))
+
+	var buf strings.Builder
+	buf.WriteString("--- " + oldName + "\n")
+	buf.WriteString("+++ " + newName + "\n")
+	for _, h := range hunks(ops) {
+		h.write(&buf)
+	}
+
+	return buf.String()
+}
+
+// opKind вид операции над строкой
+type opKind byte
+
+const (
+	opKeep   opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// op операция над строкой: строка остаётся, удаляется либо вставляется. Номера строк отсчитываются от нуля.
+type op struct {
+	kind    opKind
+	line    string
+	oldLine int
+	newLine int
+}
+
+// splitLines разбиение текста на строки, перевод строки остаётся в конце каждой
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// lineOps операции превращения строк a в строки b по наибольшей общей подпоследовательности
+func lineOps(a, b []string) []op {
+	// общие начало и конец не участвуют в поиске подпоследовательности
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	am := a[prefix : len(a)-suffix]
+	bm := b[prefix : len(b)-suffix]
+
+	// lcs[i][j] длина наибольшей общей подпоследовательности am[i:] и bm[j:]
+	lcs := make([][]int32, len(am)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(bm)+1)
+	}
+	for i := len(am) - 1; i >= 0; i-- {
+		for j := len(bm) - 1; j >= 0; j-- {
+			switch {
+			case am[i] == bm[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	res := make([]op, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		res = append(res, op{kind: opKeep, line: a[i], oldLine: i, newLine: i})
+	}
+
+	i, j := 0, 0
+	for i < len(am) || j < len(bm) {
+		switch {
+		case i < len(am) && j < len(bm) && am[i] == bm[j]:
+			res = append(res, op{kind: opKeep, line: am[i], oldLine: prefix + i, newLine: prefix + j})
+			i++
+			j++
+		case j == len(bm) || (i < len(am) && lcs[i+1][j] >= lcs[i][j+1]):
+			res = append(res, op{kind: opDelete, line: am[i], oldLine: prefix + i, newLine: prefix + j})
+			i++
+		default:
+			res = append(res, op{kind: opInsert, line: bm[j], oldLine: prefix + i, newLine: prefix + j})
+			j++
+		}
+	}
+
+	for k := 0; k < suffix; k++ {
+		res = append(res, op{
+			kind:    opKeep,
+			line:    a[len(a)-suffix+k],
+			oldLine: len(a) - suffix + k,
+			newLine: len(b) - suffix + k,
+		})
+	}
+
+	return res
+}
+
+// hunk блок изменений вместе с окружающими их неизменными строками
+type hunk struct {
+	ops []op
+}
+
+// hunks группировка операций в блоки, изменения разделённые не более чем 2*context неизменными строками
+// попадают в один блок
+func hunks(ops []op) []hunk {
+	var res []hunk
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opKeep {
+			i++
+			continue
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// ищем конец блока: за последним изменением должно идти больше 2*context неизменных строк либо конец
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opKeep {
+				end++
+				continue
+			}
+
+			next := end
+			for next < len(ops) && ops[next].kind == opKeep {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				break
+			}
+			end = next
+		}
+
+		stop := end + context
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		res = append(res, hunk{ops: ops[start:stop]})
+		i = stop
+	}
+
+	return res
+}
+
+// write запись блока в формате unified diff
+func (h hunk) write(buf *strings.Builder) {
+	first := h.ops[0]
+	var oldCount, newCount int
+	for _, o := range h.ops {
+		switch o.kind {
+		case opKeep:
+			oldCount++
+			newCount++
+		case opDelete:
+			oldCount++
+		case opInsert:
+			newCount++
+		}
+	}
+
+	buf.WriteString(fmt.Sprintf(
+		"@@ -%s +%s @@\n",
+		hunkRange(first.oldLine, oldCount),
+		hunkRange(first.newLine, newCount),
+	))
+	for _, o := range h.ops {
+		buf.WriteByte(byte(o.kind))
+		buf.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			buf.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange диапазон строк блока: пустой диапазон указывает на строку перед ним
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line)
+	case 1:
+		return fmt.Sprintf("%d", line+1)
+	default:
+		return fmt.Sprintf("%d,%d", line+1, count)
+	}
+}
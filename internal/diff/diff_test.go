@@ -0,0 +1,130 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	// lines строки с переводом строки в конце каждой
+	lines := func(ls ...string) string {
+		return strings.Join(ls, "\n") + "\n"
+	}
+
+	tests := []struct {
+		name    string
+		oldName string
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "same",
+			oldName: "a/file",
+			oldText: lines("1", "2"),
+			newText: lines("1", "2"),
+			want:    "",
+		},
+		{
+			name:    "new-file",
+			oldName: "/dev/null",
+			oldText: "",
+			newText: lines("1", "2"),
+			want: lines(
+				"--- /dev/null",
+				"+++ b/file",
+				"@@ -0,0 +1,2 @@",
+				"+1",
+				"+2",
+			),
+		},
+		{
+			name:    "deleted-tail",
+			oldName: "a/file",
+			oldText: lines("1", "2", "3", "4", "5", "6"),
+			newText: lines("1", "2"),
+			want: lines(
+				"--- a/file",
+				"+++ b/file",
+				"@@ -1,6 +1,2 @@",
+				" 1",
+				" 2",
+				"-3",
+				"-4",
+				"-5",
+				"-6",
+			),
+		},
+		{
+			name:    "no-trailing-newline",
+			oldName: "a/file",
+			oldText: lines("1", "2"),
+			newText: "1\n2",
+			want: lines(
+				"--- a/file",
+				"+++ b/file",
+				"@@ -1,2 +1,2 @@",
+				" 1",
+				"-2",
+				"+2",
+				`\ No newline at end of file`,
+			),
+		},
+		{
+			name:    "changes-2-contexts-apart",
+			oldName: "a/file",
+			oldText: lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10"),
+			newText: lines("x", "2", "3", "4", "5", "6", "7", "y", "9", "10"),
+			want: lines(
+				"--- a/file",
+				"+++ b/file",
+				"@@ -1,10 +1,10 @@",
+				"-1",
+				"+x",
+				" 2",
+				" 3",
+				" 4",
+				" 5",
+				" 6",
+				" 7",
+				"-8",
+				"+y",
+				" 9",
+				" 10",
+			),
+		},
+		{
+			name:    "changes-more-than-2-contexts-apart",
+			oldName: "a/file",
+			oldText: lines("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"),
+			newText: lines("x", "2", "3", "4", "5", "6", "7", "8", "y", "10", "11"),
+			want: lines(
+				"--- a/file",
+				"+++ b/file",
+				"@@ -1,4 +1,4 @@",
+				"-1",
+				"+x",
+				" 2",
+				" 3",
+				" 4",
+				"@@ -6,6 +6,6 @@",
+				" 6",
+				" 7",
+				" 8",
+				"-9",
+				"+y",
+				" 10",
+				" 11",
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(tt.oldName, "b/file", []byte(tt.oldText), []byte(tt.newText))
+			if got != tt.want {
+				t.Errorf("unexpected diff\ngot:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package diff provides unified diffs of text files
+package diff
@@ -2,11 +2,15 @@ package generator
 
 import (
 	"fmt"
+	"go/ast"
 	"go/token"
 	"go/types"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"golang.org/x/tools/go/packages"
+
 	"github.com/sirkon/errors"
 	"github.com/sirkon/gogh"
 	"github.com/sirkon/message"
@@ -15,8 +19,18 @@ import (
 )
 
 // New конструктор генератора сущностей
-func New(primPkg, primName, secPkg, secName, method string, customErrs bool, xclude []string) (*Generator, error) {
-	var g Generator
+func New(
+	primPkg, primName, secPkg, secName, method string,
+	customErrs bool,
+	xclude []string,
+	opts ...Option,
+) (*Generator, error) {
+	g := Generator{
+		unwrapping: map[*types.Named]struct{}{},
+	}
+	for _, opt := range opts {
+		opt(&g)
+	}
 
 	prim := structDescription{
 		pkg:  primPkg,
@@ -27,20 +41,103 @@ func New(primPkg, primName, secPkg, secName, method string, customErrs bool, xcl
 		name: secName,
 	}
 
-	structs, err := g.getOrigStructs(prim, sec)
+	descrs := []structDescription{prim}
+	if secPkg != "" {
+		descrs = append(descrs, sec)
+	}
+
+	structs, err := g.getOrigStructs(descrs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "look for primary and secondary structs definitions")
 	}
 
+	g.prim = structs[prim.String()]
+	if _, ok := g.prim.Underlying().(*types.Struct); !ok {
+		return nil, errors.Newf("primary %s must be a structure", prim)
+	}
+
+	// secondary-тип может быть и словарём со строковыми ключами: именованным, либо заданным выражением
+	// вида map[string]T без указания пакета
+	if secPkg != "" {
+		g.sec = structs[sec.String()]
+		g.secMap, _ = g.sec.Underlying().(*types.Map)
+	} else {
+		g.secMap, err = g.getMapLiteral(g.prim.Obj().Pkg(), secName)
+		if err != nil {
+			return nil, errors.Wrap(err, "get secondary map type")
+		}
+	}
+	if g.secMap != nil {
+		// псевдонимы вроде any не могут быть отрисованы, заменяем их на исходные типы
+		g.secMap = types.NewMap(unalias(g.secMap.Key()), unalias(g.secMap.Elem()))
+	}
+
 	g.customErrs = customErrs
 	g.xclude = map[string]struct{}{}
 	for _, s := range xclude {
 		g.xclude[s] = struct{}{}
 	}
 
-	g.prim = structs[prim.String()]
-	g.sec = structs[sec.String()]
 	g.method = method
+
+	if g.outPath == "" {
+		g.outPath = g.prim.Obj().Pkg().Path()
+	}
+	if g.sec != nil && g.secMap == nil {
+		g.localTypes = g.prim.Obj().Pkg().Path() == g.outPath && g.sec.Obj().Pkg().Path() == g.outPath
+	}
+	if !g.prim.Obj().Exported() && g.prim.Obj().Pkg().Path() != g.outPath {
+		return nil, errors.Newf("primary %s is not exported and cannot be used out of its package", prim)
+	}
+	if g.sec != nil && !g.sec.Obj().Exported() && g.sec.Obj().Pkg().Path() != g.outPath {
+		return nil, errors.Newf("secondary %s is not exported and cannot be used out of its package", sec)
+	}
+	if g.method != "" && g.prim.Obj().Pkg().Path() != g.outPath {
+		return nil, errors.Newf("method %s cannot be defined out of the package of primary %s", g.method, prim)
+	}
+
+	// в пакете сгенерированного кода могут быть уже написанные функции пользователя
+	g.out, err = g.getOutPackage()
+	if err != nil {
+		return nil, errors.Wrap(err, "look for output package")
+	}
+
+	switch g.direction {
+	case "", DirectionBoth, DirectionPrimToSec, DirectionSecToPrim:
+	default:
+		return nil, errors.Newf("unsupported conversion direction '%s'", g.direction)
+	}
+
+	// структуры без публичных полей могут быть типами-значениями, которые строятся конструктором и читаются
+	// методами доступа
+	g.primVO = g.getValueObject(g.prim)
+	if g.secMap == nil {
+		g.secVO = g.getValueObject(g.sec)
+	}
+
+	if len(g.mappings) > 0 && g.secMap != nil {
+		return nil, errors.New("fields mapping cannot be used with maps")
+	}
+	for prim, sec := range g.mappings {
+		if !hasField(g.structOf(g.prim), prim) {
+			return nil, errors.Newf("field %s of fields mapping is not found in primary %s", prim, g.prim)
+		}
+		if !hasField(g.structOf(g.sec), sec) {
+			return nil, errors.Newf("field %s of fields mapping is not found in secondary %s", sec, g.sec)
+		}
+	}
+
+	if g.timeAll != "" {
+		if err := checkTimePolicy(g.timeAll); err != nil {
+			return nil, errors.Wrap(err, "check time representation")
+		}
+	}
+	for field, policy := range g.timeFields {
+		if err := checkTimePolicy(policy); err != nil {
+			return nil, errors.Wrapf(err, "check time representation of field %s", field)
+		}
+	}
+
 	return &g, nil
 }
 
@@ -48,78 +145,232 @@ func New(primPkg, primName, secPkg, secName, method string, customErrs bool, xcl
 type Generator struct {
 	prim       *types.Named
 	sec        *types.Named
+	secMap     *types.Map
+	primVO     *valueObject
+	secVO      *valueObject
 	method     string
 	customErrs bool
 	xclude     map[string]struct{}
-
-	fs    *token.FileSet
-	fqsec int
+	protoNames bool
+	tagKey     string
+	direction  Direction
+	// alwaysErrors функции конвертации возвращают ошибку даже если завершиться ею не могут
+	alwaysErrors bool
+	// byValue функции конвертации, которые не могут завершиться ошибкой, принимают и возвращают значения
+	byValue bool
+	// tests генерация тестов конвертации туда и обратно
+	tests bool
+	// batch генерация функций пакетной конвертации срезов и словарей
+	batch bool
+	// outPath путь пакета сгенерированного кода, по умолчанию это пакет primary-структуры
+	outPath string
+	// typedFileName имя файла сгенерированного кода включает имя primary-типа
+	typedFileName bool
+
+	// localTypes обе структуры находятся в пакете сгенерированного кода, их непубличные поля доступны
+	localTypes bool
+
+	strconvAll    bool
+	strconvFields map[string]struct{}
+
+	timeAll    string
+	timeFields map[string]string
+
+	// sqlFields primary-поля, для которых значения driver.Valuer сопоставляются с любым примитивом driver.Value
+	sqlFields map[string]struct{}
+
+	// unwrapping структуры-обёртки разворачиваемые в данный момент
+	unwrapping map[*types.Named]struct{}
+	// fallible генерируемая в данный момент функция может завершиться ошибкой
+	fallible bool
+	// hooks функции пользователя, вызовы которых были сгенерированы
+	hooks []manualHook
+	// primToSecFunc и secToPrimFunc сгенерированные функции конвертации
+	primToSecFunc *generatedFunc
+	secToPrimFunc *generatedFunc
+	// primToSecFallible и secToPrimFallible primary-поля, конвертация которых в данном направлении может
+	// завершиться ошибкой
+	primToSecFallible map[*types.Var]struct{}
+	secToPrimFallible map[*types.Var]struct{}
+	// files пути файлов сгенерированного кода относительно корня модуля
+	files []string
+
+	// mappings заданные вручную сопоставления полей primary полям secondary
+	mappings map[string]string
+	// loaded пакеты, в которых ищутся структуры
+	loaded *Packages
+	// out пакет сгенерированного кода, nil если его ещё нет
+	out *packages.Package
+
+	fs     *token.FileSet
+	syntax map[string][]*ast.File
+	fqsec  int
 }
 
 // Generate генерация кода
 func (g *Generator) Generate(prj *gogh.Module[*imports.Imports]) error {
-	message.Infof("generate conversions between primary %s and secondary %s structures", g.prim, g.sec)
-
-	matches, oos := g.getFieldsMatches(nil)
-	missingPrim, missingSec := g.reportMatchingInfo(matches, oos)
+	if g.secMap != nil {
+		message.Infof("generate conversions between primary %s structure and %s", g.prim, g.secMapType())
+	} else {
+		message.Infof("generate conversions between primary %s and secondary %s structures", g.prim, g.sec)
+	}
 
-	// вычисляем относительный путь пакета с primary-структурой
-	pkgName := g.prim.Obj().Pkg()
-	relPkg := strings.TrimPrefix(strings.TrimPrefix(pkgName.Path(), prj.Name()), "/")
+	// вычисляем относительный путь пакета сгенерированного кода, он должен быть в текущем модуле
+	if g.outPath != prj.Name() && !strings.HasPrefix(g.outPath, prj.Name()+"/") {
+		return errors.Newf("output package %s is out of the current module %s", g.outPath, prj.Name())
+	}
+	relPkg := strings.TrimPrefix(strings.TrimPrefix(g.outPath, prj.Name()), "/")
 
-	// вычисляем имя файла для генерируемой части конвертации
-	position := g.fs.Position(g.prim.Obj().Pos())
-	_, fileName := filepath.Split(position.Filename)
-	fileName = strings.TrimSuffix(fileName, ".go") + "_metamorphosies.go"
+	fileName := g.fileName()
+	pkgName := g.prim.Obj().Pkg().Name()
+	if g.outPath != g.prim.Obj().Pkg().Path() {
+		pkgName = outPackageName(g.outPath)
+	}
 
-	pkg, err := prj.Package(g.prim.Obj().Pkg().Name(), relPkg)
+	pkg, err := prj.Package(pkgName, relPkg)
 	if err != nil {
-		return errors.Wrap(err, "setup package of primary structure")
+		return errors.Wrap(err, "setup output package")
 	}
 
 	r := pkg.Go(fileName, gogh.Autogen(app.Name+" generate"))
+	g.files = append(g.files, path.Join(relPkg, fileName))
 
-	if err := g.generate(r, matches, oos, missingPrim, missingSec); err != nil {
-		return errors.Wrap(err, "generate source code")
+	if g.secMap != nil {
+		if err := g.generateMap(r); err != nil {
+			return errors.Wrap(err, "generate source code")
+		}
+	} else {
+		matches, oos := g.getFieldsMatches(g.manualMappings())
+		methods := g.getMethodsMatches(matches, oos)
+		g.reportMatchingInfo(matches, oos, methods)
+
+		if err := g.generate(r, matches, oos, methods); err != nil {
+			return errors.Wrap(err, "generate source code")
+		}
+
+		if g.tests {
+			if fields := g.roundTripFields(matches); len(fields) > 0 {
+				testName := strings.TrimSuffix(fileName, "_metamorphosies.go") + "_metamorph_test.go"
+				g.renderRoundTripTest(pkg.Go(testName, gogh.Autogen(app.Name+" generate")), fields)
+				g.files = append(g.files, path.Join(relPkg, testName))
+			}
+		}
+	}
+
+	// заготовки функций пользователя пишутся рядом, если их ещё нет
+	if hooks := g.undeclaredHooks(); len(hooks) > 0 {
+		hooksName := g.hooksFileName(strings.TrimSuffix(fileName, "_metamorphosies.go"))
+		g.renderHooksStubs(pkg.Go(hooksName, gogh.Shy), hooks)
+		g.files = append(g.files, path.Join(relPkg, hooksName))
 	}
 
 	return nil
 }
 
+// manualMappings заданные вручную сопоставления полей в виде gogh.Underscored их имён
+func (g *Generator) manualMappings() map[string]string {
+	res := map[string]string{}
+	for prim, sec := range g.mappings {
+		res[gogh.Underscored(prim)] = gogh.Underscored(sec)
+	}
+
+	return res
+}
+
+// fileName имя файла для генерируемой части конвертации
+func (g *Generator) fileName() string {
+	position := g.fs.Position(g.prim.Obj().Pos())
+	_, fileName := filepath.Split(position.Filename)
+	fileName = strings.TrimSuffix(fileName, ".go")
+	if g.typedFileName {
+		fileName += "_" + gogh.Underscored(g.prim.Obj().Name())
+	}
+
+	if g.outPath != g.prim.Obj().Pkg().Path() {
+		// в отдельный пакет пишутся конвертации разных пакетов, имена файлов не должны пересекаться
+		fileName = g.prim.Obj().Pkg().Name() + "_" + fileName
+	}
+
+	return fileName + "_metamorphosies.go"
+}
+
+// OutputFile путь пакета и имя файла, в который будет сгенерирован код конвертаций
+func (g *Generator) OutputFile() string {
+	return path.Join(g.outPath, g.fileName())
+}
+
+// String описание пары конвертируемых типов
+func (g *Generator) String() string {
+	return g.prim.String() + " ↔ " + g.secMapType()
+}
+
+// Files пути файлов относительно корня модуля, которые будут записаны при отрисовке сгенерированного кода
+func (g *Generator) Files() []string {
+	return g.files
+}
+
 // generate генерация кода преобразований структур
 func (g *Generator) generate(
 	r *gogh.GoRenderer[*imports.Imports],
 	matches []fieldMatchInfo,
 	oos []fieldSecondaryOneof,
-	primMismatch bool,
-	secMismatch bool,
+	methods []fieldMethodMatch,
 ) error {
 	var secname string
-	if g.sec.Obj().Pkg().Path() != g.prim.Obj().Pkg().Path() {
+	if g.sec.Obj().Pkg().Path() != g.outPath {
 		r.Imports().Add(g.sec.Obj().Pkg().Path()).Ref("secpkg")
 		secname = r.S("$secpkg.$0", g.sec.Obj().Name())
 	} else {
 		secname = g.sec.Obj().Name()
 	}
-	primname := g.prim.Obj().Name()
+	primname := g.primName(r)
+	primunder := strings.ReplaceAll(primname, ".", "_")
 	secunder := strings.ReplaceAll(secname, ".", "_")
 
-	if g.method != "" {
-		r.L(`// $0 conversion of $1 into $2`, g.method, primname, secname)
-		r.L(`func (x *$0) $1() (*$2, error) {`, primname, g.method, secname)
-	} else {
-		r.L(`// $0To${1|P} conversion of $0 into $2`, primname, secunder, secname)
-		r.L(`func $0To${1|P}(x *$0) (*$2, error) {`, primname, secunder, secname)
+	if g.primToSec() {
+		g.generatePrimToSec(r, primname, primunder, secname, secunder, matches, oos, methods)
 	}
 
-	r.L(`    if x == nil {`)
-	r.L(`        return nil, nil`)
-	r.L(`    }`)
-	r.N()
-	r.L(`    var res $0`, secname)
+	if g.secToPrim() {
+		if g.primToSec() {
+			r.N()
+		}
+		g.generateSecToPrim(r, primname, primunder, secname, secunder, matches, oos, methods)
+	}
+
+	return nil
+}
+
+// generatePrimToSec генерация функции или метода конвертации primary → secondary
+func (g *Generator) generatePrimToSec(
+	r *gogh.GoRenderer[*imports.Imports],
+	primname string,
+	primunder string,
+	secname string,
+	secunder string,
+	matches []fieldMatchInfo,
+	oos []fieldSecondaryOneof,
+	methods []fieldMethodMatch,
+) {
+	sig := funcSignature{
+		method:    g.method,
+		name:      g.primToSecName(r, primunder, secunder),
+		src:       primname,
+		srcStruct: true,
+		dst:       secname,
+		dstStruct: true,
+	}
+	sig.slices, sig.maps = g.batchNames(r, primunder, secunder, true)
 
-	prim := g.prim.Underlying().(*types.Struct)
+	// сигнатура зависит от того, может ли конвертация завершиться ошибкой, поэтому заголовок отрисовывается
+	// после тела функции
+	head := r.Z()
+	g.fallible = false
+	g.primToSecFallible = map[*types.Var]struct{}{}
+
+	prim := g.structOf(g.prim)
 	oopassed := map[string]struct{}{}
+	var hooked []*types.Var
 	for i := 0; i < prim.NumFields(); i++ {
 		field := prim.Field(i)
 
@@ -133,30 +384,29 @@ func (g *Generator) generate(
 		var xclude bool
 		if match != nil {
 			_, xclude = g.xclude[match.prim.Name()]
-			if xclude {
-				primMismatch = true
-				secMismatch = true
-			}
 		}
 
 		r.N()
 		switch {
-		case match != nil && !xclude:
-			if _, ok := match.descr.(*FieldMatchNoMatch); ok {
-				continue
+		case match != nil && match.sec == nil:
+			// полю нет соответствия, его значение передаётся пользователю если не нашлось сеттера
+			if xclude || isRequired(match.prim) && !methodCovers(methods, match.prim, false, true) {
+				hooked = append(hooked, match.prim)
 			}
 
+		case match != nil && (xclude || isNoMatch(match.descr)):
+			g.renderValueHook(r, g.prim, match.prim, g.sec, match.sec, primunder, secunder)
+			g.primToSecFallible[match.prim] = struct{}{}
+
+		case match != nil:
 			r.L(`// convert field $0`, match.prim.Name())
-			g.convertValue(
-				r,
-				"res."+match.sec.Name(),
-				match.sec.Type(),
-				"x."+match.prim.Name(),
-				match.prim.Type(),
-				match.descr,
-				"field "+match.prim.Name(),
-				false,
-			)
+			if g.trackFallible(func() {
+				g.convertIntoField(r, g.sec, match.sec, func(dst string, dstType types.Type) {
+					g.convertFieldValue(r, dst, dstType, g.prim, match.prim, match.descr, "field "+match.prim.Name())
+				})
+			}) {
+				g.primToSecFallible[match.prim] = struct{}{}
+			}
 
 		case oomatch != nil:
 			// поле соответствующее ветви oneof
@@ -176,16 +426,16 @@ func (g *Generator) generate(
 					r.L(`case x.$0 != nil && x.$1 != nil:`, b1.prim.Name(), b2.prim.Name())
 					if g.customErrs {
 						r.Imports().Errors().Ref("errors")
-						r.L(
-							`    return nil, $errors.New("fields $0 and $1 refer to respective branches of oneof $2 and must not coexist")`,
+						g.renderErrorReturn(r,
+							`$errors.New("fields $0 and $1 refer to respective branches of oneof $2 and must not coexist")`,
 							b1.branch,
 							b2.branch,
 							oomatch.sec.Name(),
 						)
 					} else {
 						r.Imports().Fmt().Ref("fmt")
-						r.L(
-							`    return nil, $fmt.Errorf("fields $0 and $1 refer to respective branches of oneof $2 and must not coexist")`,
+						g.renderErrorReturn(r,
+							`$fmt.Errorf("fields $0 and $1 refer to respective branches of oneof $2 and must not coexist")`,
 							b1.branch,
 							b2.branch,
 							oomatch.sec.Name(),
@@ -211,7 +461,7 @@ func (g *Generator) generate(
 					"field "+b.prim.Name()+" into respective oneof branch",
 					true,
 				)
-				r.L(`res.$0 = &branch$1`, oomatch.sec.Name(), b.branch)
+				r.L(`$0 = &branch$1`, g.fieldDestination(g.sec, oomatch.sec), b.branch)
 				if i < len(oomatch.branches)-1 {
 					r.N()
 				}
@@ -220,39 +470,61 @@ func (g *Generator) generate(
 		}
 	}
 
-	if primMismatch {
+	g.convertMethodsValues(r, methods, true)
 
-		r.N()
-		r.L(`// there's fields mismatch, call user defined code'`)
-		r.L(`if err := manual$0To${1|P}(x, &res); err != nil {`, primname, secunder)
-		if g.customErrs {
-			r.Imports().Errors().Ref("errors")
-			r.L(`    return nil, $errors.Wrap(err, "run user defined conversion")`)
-		} else {
-			r.Imports().Fmt().Ref("fmt")
-			r.L(`    return nil, $fmt.Errorf("run user defined conversion: %w", err)`)
-		}
-		r.L(`}`)
+	resref := "&res"
+	if g.secVO != nil {
+		resref = g.constructValueObject(r, g.secVO, secname)
+	}
+
+	g.renderFieldsHooks(r, g.prim, hooked, g.sec, primunder, secunder, resref)
+
+	shape := g.currentShape()
+	g.renderFuncHead(head, shape, sig)
+	if g.secVO != nil {
+		g.declareValueObjectArgs(head, g.secVO)
+	} else {
+		head.L(`    var res $0`, secname)
 	}
 
 	r.N()
-	r.L(`    return &res, nil`)
+	g.renderFuncReturn(r, shape, sig, resref)
 	r.L(`}`)
 
-	r.N()
-	r.L(`// ${0|P}To$1 conversion of $2 into $1`, secunder, primname, secname)
-	r.L(`func ${0|P}To$1(x *$2) (*$1, error) {`, secunder, primname, secname)
-	r.L(`    if x == nil {`)
-	r.L(`        return nil, nil`)
-	r.L(`    }`)
-	r.N()
-	r.L(`    var res $0`, primname)
+	g.renderBatchHelpers(r, shape, sig)
+	g.primToSecFunc = &generatedFunc{sig: sig, shape: shape}
+}
 
-	sec := g.sec.Underlying().(*types.Struct)
+// generateSecToPrim генерация функции конвертации secondary → primary
+func (g *Generator) generateSecToPrim(
+	r *gogh.GoRenderer[*imports.Imports],
+	primname string,
+	primunder string,
+	secname string,
+	secunder string,
+	matches []fieldMatchInfo,
+	oos []fieldSecondaryOneof,
+	methods []fieldMethodMatch,
+) {
+	sig := funcSignature{
+		name:      g.secToPrimName(r, secunder, primunder),
+		src:       secname,
+		srcStruct: true,
+		dst:       primname,
+		dstStruct: true,
+	}
+	sig.slices, sig.maps = g.batchNames(r, secunder, primunder, false)
+
+	head := r.Z()
+	g.fallible = false
+	g.secToPrimFallible = map[*types.Var]struct{}{}
+
+	sec := g.structOf(g.sec)
+	var hooked []*types.Var
 
 	for i := 0; i < sec.NumFields(); i++ {
 		field := sec.Field(i)
-		if field.Name() == "" || !field.Exported() {
+		if field.Name() == "" || !g.isAccessible(field) || g.isSkippedField(sec, field) {
 			continue
 		}
 
@@ -261,33 +533,27 @@ func (g *Generator) generate(
 		var xclude bool
 		if match != nil {
 			_, xclude = g.xclude[match.prim.Name()]
-			if xclude {
-				primMismatch = true
-				secMismatch = true
-			}
 		}
 
 		switch {
-		case match != nil && !xclude:
-			if _, ok := match.descr.(*FieldMatchNoMatch); ok {
-				continue
-			}
+		case match != nil && (xclude || isNoMatch(match.descr)):
+			r.N()
+			g.renderValueHook(r, g.sec, field, g.prim, match.prim, secunder, primunder)
+			g.secToPrimFallible[match.prim] = struct{}{}
 
+		case match != nil:
 			// некоторые виды descr должны быть преобразованы зеркальным образом для конвертации sec -> prim
 			descr := reflectDescr(match.descr)
 
 			r.N()
 			r.L(`// convert field $0`, field.Name())
-			g.convertValue(
-				r,
-				"res."+match.prim.Name(),
-				match.prim.Type(),
-				"x."+match.sec.Name(),
-				match.sec.Type(),
-				descr,
-				"field "+match.sec.Name(),
-				false,
-			)
+			if g.trackFallible(func() {
+				g.convertIntoField(r, g.prim, match.prim, func(dst string, dstType types.Type) {
+					g.convertFieldValue(r, dst, dstType, g.sec, match.sec, descr, "field "+match.sec.Name())
+				})
+			}) {
+				g.secToPrimFallible[match.prim] = struct{}{}
+			}
 
 		case oomatch != nil:
 			r.N()
@@ -297,7 +563,7 @@ func (g *Generator) generate(
 				r.L(`case *$0:`, g.safeBranch(r, b.branch))
 				g.convertValue(
 					r,
-					"res."+b.prim.Name(),
+					g.fieldDestination(g.prim, b.prim),
 					b.prim.Type(),
 					"v."+b.branch,
 					b.sec.Type(),
@@ -307,29 +573,36 @@ func (g *Generator) generate(
 				)
 			}
 			r.L(`}`)
+
+		case isRequired(field) && !methodCovers(methods, field, true, false):
+			// полю нет соответствия, его значение передаётся пользователю
+			hooked = append(hooked, field)
 		}
 	}
 
-	if secMismatch {
+	g.convertMethodsValues(r, methods, false)
 
-		r.N()
-		r.L(`// there's a mismatch, call for user defined conversions'`)
-		r.L(`if err := manual${0|P}To$1(x, &res); err != nil {`, secunder, primname)
-		if g.customErrs {
-			r.Imports().Errors().Ref("errors")
-			r.L(`    return nil, $errors.Wrap(err, "run user defined conversion")`)
-		} else {
-			r.Imports().Fmt().Ref("fmt")
-			r.L(`    return nil, $fmt.Errorf("run user defined conversion: %w", err)`)
-		}
-		r.L(`}`)
+	resref := "&res"
+	if g.primVO != nil {
+		resref = g.constructValueObject(r, g.primVO, primname)
+	}
+
+	g.renderFieldsHooks(r, g.sec, hooked, g.prim, secunder, primunder, resref)
+
+	shape := g.currentShape()
+	g.renderFuncHead(head, shape, sig)
+	if g.primVO != nil {
+		g.declareValueObjectArgs(head, g.primVO)
+	} else {
+		head.L(`    var res $0`, primname)
 	}
 
 	r.N()
-	r.L(`    return &res, nil`)
+	g.renderFuncReturn(r, shape, sig, resref)
 	r.L(`}`)
 
-	return nil
+	g.renderBatchHelpers(r, shape, sig)
+	g.secToPrimFunc = &generatedFunc{sig: sig, shape: shape}
 }
 
 // при генерации метода primary -> secondary привязываемся к порядку полей в primary
@@ -355,11 +628,16 @@ func (g *Generator) getPrimFieldConversionDiscrs(
 	var oomatch *fieldSecondaryOneof
 	if match == nil {
 		for _, oo := range oos {
+			var isBranch bool
 			for _, b := range oo.branches {
-				if b.prim != primfield {
-					continue
+				if b.prim == primfield {
+					isBranch = true
+					break
 				}
 			}
+			if !isBranch {
+				continue
+			}
 
 			for _, b := range oo.branches {
 				// исключаем поля oneof из дальнейшей обработки, т.к. они все будут охвачены на последующих
@@ -380,10 +658,10 @@ func (g *Generator) getSecFieldConversionDiscrs(
 	matches []fieldMatchInfo,
 	oos []fieldSecondaryOneof,
 ) (*fieldMatchInfo, *fieldSecondaryOneof) {
-	// сначала ищем между соответствиями в регулярных полях
+	// сначала ищем между соответствиями в регулярных полях, несопоставленные primary-поля идут вперемешку с ними
 	for _, m := range matches {
 		if m.sec == nil {
-			break
+			continue
 		}
 
 		if m.sec.Id() == secfield.Id() {
@@ -403,7 +681,7 @@ func (g *Generator) getSecFieldConversionDiscrs(
 
 // funcName возвращает полное имя функции преобразования с учётом размещения в разных с primary-типом пакетах
 func (g *Generator) funcName(r *gogh.GoRenderer[*imports.Imports], f *types.Func) string {
-	if f.Pkg().Path() == g.prim.Obj().Pkg().Path() {
+	if f.Pkg().Path() == g.outPath {
 		return f.Name()
 	}
 
@@ -485,6 +763,48 @@ func reflectDescr(descr FieldMatchDescription) FieldMatchDescription {
 			Key:  reflectDescr(v.Key),
 			Elem: reflectDescr(v.Elem),
 		}
+	case *FieldMatchWrapper:
+		return &FieldMatchWrapper{
+			Secondary:   !v.Secondary,
+			Value:       v.Value,
+			Accessor:    v.Accessor,
+			Constructor: v.Constructor,
+			Elem:        reflectDescr(v.Elem),
+		}
+	case *FieldMatchUnion:
+		res := &FieldMatchUnion{
+			Secondary: !v.Secondary,
+		}
+		for _, b := range v.Branches {
+			res.Branches = append(res.Branches, &FieldMatchUnionBranch{
+				Field: b.Field,
+				Impl:  b.Impl,
+				Value: b.Value,
+				Elem:  reflectDescr(b.Elem),
+			})
+		}
+		return res
+	case *FieldMatchText:
+		return &FieldMatchText{
+			Secondary: !v.Secondary,
+			Stringer:  v.Stringer,
+		}
+	case *FieldMatchSQL:
+		return &FieldMatchSQL{
+			Secondary: !v.Secondary,
+		}
+	case *FieldMatchBytes:
+		return v
+	case *FieldMatchStrconv:
+		return &FieldMatchStrconv{
+			Secondary: !v.Secondary,
+		}
+	case *FieldMatchTime:
+		return &FieldMatchTime{
+			Secondary: !v.Secondary,
+			Duration:  v.Duration,
+			Policy:    v.Policy,
+		}
 	default:
 		return nil
 	}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"path/filepath"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/message"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// batchNames имена функций пакетной конвертации срезов и словарей для функции конвертации из src в dst. Оба
+// имени получают суффикс Slice или Map, а не форму множественного числа, чтобы не совпасть с функцией
+// конвертации типов, чьи имена уже во множественном числе.
+func (g *Generator) batchNames(
+	r *gogh.GoRenderer[*imports.Imports],
+	src string,
+	dst string,
+	primToSec bool,
+) (slices string, maps string) {
+	name := g.secToPrimName
+	if primToSec {
+		name = g.primToSecName
+	}
+
+	return name(r, src+"Slice", dst+"Slice"), name(r, src+"Map", dst+"Map")
+}
+
+// renderBatchHelpers генерация функций пакетной конвертации срезов и словарей значений через сгенерированную
+// функцию sig формы shape. Ошибка конвертации элемента дополняется его индексом либо ключом.
+func (g *Generator) renderBatchHelpers(r *gogh.GoRenderer[*imports.Imports], shape funcShape, sig funcSignature) {
+	if !g.batch {
+		return
+	}
+	for _, name := range []string{sig.slices, sig.maps} {
+		if g.declaredByUser(name) {
+			message.Warningf("%s is already declared in the output package, will not generate batch helpers", name)
+			return
+		}
+	}
+
+	param, result := sig.types(shape)
+
+	r.N()
+	r.L(`// $0 conversion of []$1 into []$2`, sig.slices, param, result)
+	if shape == shapeFallible {
+		r.L(`func $0(xs []$1) ([]$2, error) {`, sig.slices, param, result)
+	} else {
+		r.L(`func $0(xs []$1) []$2 {`, sig.slices, param, result)
+	}
+	g.renderBatchBody(r, shape, sig, "make([]"+result+", len(xs))", "i", "%d", "index")
+	r.L(`}`)
+
+	r.N()
+	r.L(`// $0 conversion of map[K]$1 into map[K]$2`, sig.maps, param, result)
+	if shape == shapeFallible {
+		r.L(`func $0[K comparable](xs map[K]$1) (map[K]$2, error) {`, sig.maps, param, result)
+	} else {
+		r.L(`func $0[K comparable](xs map[K]$1) map[K]$2 {`, sig.maps, param, result)
+	}
+	g.renderBatchBody(r, shape, sig, "make(map[K]"+result+", len(xs))", "k", "%v", "key")
+	r.L(`}`)
+}
+
+// renderBatchBody генерация цикла конвертации элементов xs в результат, созданный выражением alloc
+func (g *Generator) renderBatchBody(
+	r *gogh.GoRenderer[*imports.Imports],
+	shape funcShape,
+	sig funcSignature,
+	alloc string,
+	key string,
+	verb string,
+	what string,
+) {
+	call := r.S("$0(x)", sig.name)
+	if sig.method != "" {
+		call = r.S("x.$0()", sig.method)
+	}
+
+	r.L(`    if xs == nil {`)
+	if shape == shapeFallible {
+		r.L(`        return nil, nil`)
+	} else {
+		r.L(`        return nil`)
+	}
+	r.L(`    }`)
+	r.N()
+	r.L(`    res := $0`, alloc)
+	r.L(`    for $0, x := range xs {`, key)
+	if shape == shapeFallible {
+		r.L(`        v, err := $0`, call)
+		r.L(`        if err != nil {`)
+		if g.customErrs {
+			r.Imports().Errors().Ref("errors")
+			g.renderErrorReturn(r, `$errors.Wrapf(err, "convert $0 $1", $2)`, what, verb, key)
+		} else {
+			r.Imports().Fmt().Ref("fmt")
+			g.renderErrorReturn(r, `$fmt.Errorf("convert $0 $1: %w", $2, err)`, what, verb, key)
+		}
+		r.L(`        }`)
+		r.L(`        res[$0] = v`, key)
+	} else {
+		r.L(`        res[$0] = $1`, key, call)
+	}
+	r.L(`    }`)
+	r.N()
+	if shape == shapeFallible {
+		r.L(`    return res, nil`)
+	} else {
+		r.L(`    return res`)
+	}
+}
+
+// declaredByUser имя name объявлено в пакете сгенерированного кода вне файла, который сейчас перезаписывается
+func (g *Generator) declaredByUser(name string) bool {
+	if g.out == nil {
+		return false
+	}
+
+	obj := g.out.Types.Scope().Lookup(name)
+	if obj == nil {
+		return false
+	}
+
+	return filepath.Base(g.out.Fset.Position(obj.Pos()).Filename) != g.fileName()
+}
@@ -0,0 +1,180 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// manualHook функция конвертации, которую пишет пользователь: для полей, которые не удалось либо запрещено
+// сопоставлять автоматически. Вызовы генерируются вместе с конвертацией, а заготовки пишутся в отдельный файл.
+type manualHook struct {
+	name   string
+	doc    string
+	params []*types.Var
+	// result тип значения конвертации, nil если возвращается только ошибка
+	result types.Type
+}
+
+// hookName имя функции конвертации поля field типа src в тип dst
+func (g *Generator) hookName(r *gogh.GoRenderer[*imports.Imports], src, field, dst string) string {
+	return r.S("convert${0|P}${1|P}To${2|P}", src, field, dst)
+}
+
+// typeName имя типа для документации: типы пакета генерируемого кода не квалифицируются
+func (g *Generator) typeName(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p.Path() == g.outPath {
+			return ""
+		}
+
+		return p.Name()
+	})
+}
+
+// fieldValue выражение значения поля field источника типа owner
+func (g *Generator) fieldValue(owner types.Type, field *types.Var) string {
+	if vo := g.valueObjectOf(owner); vo != nil {
+		return "x." + vo.accessors[field].Name() + "()"
+	}
+
+	return "x." + field.Name()
+}
+
+// renderValueHook генерация конвертации значения поля src типа srcOwner в поле dst типа dstOwner функцией
+// пользователя вида func(v T) (U, error).
+func (g *Generator) renderValueHook(
+	r *gogh.GoRenderer[*imports.Imports],
+	srcOwner types.Type,
+	src *types.Var,
+	dstOwner types.Type,
+	dst *types.Var,
+	srcname string,
+	dstname string,
+) {
+	name := g.hookName(r, srcname, src.Name(), dstname)
+	g.hooks = append(g.hooks, manualHook{
+		name: name,
+		doc: r.S(
+			"conversion of field $0 of $1 into field $2 of $3",
+			src.Name(),
+			g.typeName(srcOwner),
+			dst.Name(),
+			g.typeName(dstOwner),
+		),
+		params: []*types.Var{
+			types.NewParam(token.NoPos, nil, "v", src.Type()),
+		},
+		result: dst.Type(),
+	})
+
+	r.L(`// convert field $0 with user defined code`, src.Name())
+	g.convertIntoField(r, dstOwner, dst, func(dstExpr string, _ types.Type) {
+		r.L(`{`)
+		r.L(`    v, err := $0($1)`, name, g.fieldValue(srcOwner, src))
+		r.L(`    if err != nil {`)
+		g.renderWrappedReturn(r, "convert field "+src.Name())
+		r.L(`    }`)
+		r.L(`    $0 = v`, dstExpr)
+		r.L(`}`)
+	})
+}
+
+// renderFieldsHooks генерация передачи значений полей fields типа srcOwner, которым нет соответствия, функциям
+// пользователя вида func(v T, res *Y) error вместе с уже построенным результатом resref.
+func (g *Generator) renderFieldsHooks(
+	r *gogh.GoRenderer[*imports.Imports],
+	srcOwner types.Type,
+	fields []*types.Var,
+	dstOwner types.Type,
+	srcname string,
+	dstname string,
+	resref string,
+) {
+	if len(fields) == 0 {
+		return
+	}
+
+	r.N()
+	r.L(`// there are fields with no match, call user defined code`)
+	for _, f := range fields {
+		name := g.hookName(r, srcname, f.Name(), dstname)
+		g.hooks = append(g.hooks, manualHook{
+			name: name,
+			doc:  r.S("puts field $0 of $1 into $2", f.Name(), g.typeName(srcOwner), g.typeName(dstOwner)),
+			params: []*types.Var{
+				types.NewParam(token.NoPos, nil, "v", f.Type()),
+				types.NewParam(token.NoPos, nil, "res", types.NewPointer(dstOwner)),
+			},
+		})
+
+		r.L(`if err := $0($1, $2); err != nil {`, name, g.fieldValue(srcOwner, f), resref)
+		g.renderWrappedReturn(r, "convert field "+f.Name())
+		r.L(`}`)
+	}
+}
+
+// undeclaredHooks функции пользователя, которые ещё не объявлены в пакете сгенерированного кода: уже написанные
+// функции могут лежать в любом его файле.
+func (g *Generator) undeclaredHooks() []manualHook {
+	var res []manualHook
+	for _, hook := range g.hooks {
+		if g.out != nil && g.out.Types.Scope().Lookup(hook.name) != nil {
+			continue
+		}
+
+		res = append(res, hook)
+	}
+
+	return res
+}
+
+// hooksFileName имя файла заготовок функций пользователя для сгенерированного кода с именем base. Существующий
+// файл заготовок не перезаписывается, т.к. в нём уже может быть код пользователя, поэтому недостающие заготовки
+// пишутся в новый файл.
+func (g *Generator) hooksFileName(base string) string {
+	existing := map[string]struct{}{}
+	if g.out != nil {
+		for _, file := range g.out.GoFiles {
+			existing[filepath.Base(file)] = struct{}{}
+		}
+	}
+
+	name := base + "_metamorph_hooks.go"
+	for i := 2; ; i++ {
+		if _, ok := existing[name]; !ok {
+			return name
+		}
+
+		name = fmt.Sprintf("%s_metamorph_hooks_%d.go", base, i)
+	}
+}
+
+// renderHooksStubs генерация заготовок функций пользователя hooks. Файл пишется только если его ещё нет, чтобы
+// не перетереть уже написанный код.
+func (g *Generator) renderHooksStubs(r *gogh.GoRenderer[*imports.Imports], hooks []manualHook) {
+	for i, hook := range hooks {
+		if i > 0 {
+			r.N()
+		}
+
+		var params []string
+		for _, p := range hook.params {
+			params = append(params, r.S("$0 $1", p.Name(), r.Type(p.Type())))
+		}
+
+		r.L(`// $0 $1`, hook.name, hook.doc)
+		if hook.result != nil {
+			r.L(`func $0($1) ($2, error) {`, hook.name, strings.Join(params, ", "), r.Type(hook.result))
+		} else {
+			r.L(`func $0($1) error {`, hook.name, strings.Join(params, ", "))
+		}
+		r.L(`    panic("TODO")`)
+		r.L(`}`)
+	}
+}
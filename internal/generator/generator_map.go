@@ -0,0 +1,389 @@
+package generator
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/message"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// mapField поле primary-структуры сохраняемое в словаре под ключом key
+type mapField struct {
+	field *types.Var
+	key   string
+}
+
+// secMapType название secondary-словаря для отчётов
+func (g *Generator) secMapType() string {
+	if g.sec != nil {
+		return g.sec.String()
+	}
+
+	return g.secMap.String()
+}
+
+// generateMap генерация кода преобразований между primary-структурой и словарём со строковыми ключами
+func (g *Generator) generateMap(r *gogh.GoRenderer[*imports.Imports]) error {
+	var secname string
+	var secunder string
+	switch {
+	case g.sec == nil:
+		secname = r.Type(g.secMap)
+		secunder = "map"
+	case g.sec.Obj().Pkg().Path() != g.outPath:
+		r.Imports().Add(g.sec.Obj().Pkg().Path()).Ref("secpkg")
+		secname = r.S("$secpkg.$0", g.sec.Obj().Name())
+		secunder = strings.ReplaceAll(secname, ".", "_")
+	default:
+		secname = g.sec.Obj().Name()
+		secunder = secname
+	}
+	primname := g.primName(r)
+	primunder := strings.ReplaceAll(primname, ".", "_")
+	prim := g.prim.Underlying().(*types.Struct)
+	var secType types.Type = g.secMap
+	if g.sec != nil {
+		secType = g.sec
+	}
+	visited := map[*types.Named]struct{}{
+		g.prim: {},
+	}
+
+	message.Info("\nmap keys")
+
+	if g.primToSec() {
+		sig := funcSignature{
+			method:    g.method,
+			name:      g.primToSecName(r, primunder, secunder),
+			src:       primname,
+			srcStruct: true,
+			dst:       secname,
+		}
+		sig.slices, sig.maps = g.batchNames(r, primunder, secunder, true)
+		head := r.Z()
+		g.fallible = false
+
+		primMismatch := g.structToMap(r, "res", "x", g.prim, "", 0, visited)
+		message.Info()
+		if primMismatch {
+			message.Warning("not all primary fields can be put into the map")
+
+			r.N()
+			r.L(`// there's fields mismatch, call user defined code'`)
+			g.hooks = append(g.hooks, manualHook{
+				name: manualName(sig.name),
+				doc:  r.S("puts fields of $0 with no keys into the map", primname),
+				params: []*types.Var{
+					types.NewParam(token.NoPos, nil, "x", types.NewPointer(g.prim)),
+					types.NewParam(token.NoPos, nil, "res", secType),
+				},
+			})
+			r.L(`if err := $0(x, res); err != nil {`, manualName(sig.name))
+			g.renderWrappedReturn(r, "run user defined conversion")
+			r.L(`}`)
+		}
+
+		shape := g.currentShape()
+		g.renderFuncHead(head, shape, sig)
+		head.L(`    res := make($0, $1)`, secname, len(g.mapFields(prim)))
+
+		r.N()
+		g.renderFuncReturn(r, shape, sig, "res")
+		r.L(`}`)
+
+		g.renderBatchHelpers(r, shape, sig)
+	}
+
+	if g.secToPrim() {
+		if g.primToSec() {
+			r.N()
+		}
+		sig := funcSignature{
+			name:      g.secToPrimName(r, secunder, primunder),
+			src:       secname,
+			dst:       primname,
+			dstStruct: true,
+		}
+		sig.slices, sig.maps = g.batchNames(r, secunder, primunder, false)
+		head := r.Z()
+		g.fallible = false
+
+		if secMismatch := g.mapToStruct(r, "res", "x", g.prim, "", 0, visited); secMismatch {
+			r.N()
+			r.L(`// there's a mismatch, call for user defined conversions'`)
+			g.hooks = append(g.hooks, manualHook{
+				name: manualName(sig.name),
+				doc:  r.S("fills fields of $0 with no keys from the map", primname),
+				params: []*types.Var{
+					types.NewParam(token.NoPos, nil, "x", secType),
+					types.NewParam(token.NoPos, nil, "res", types.NewPointer(g.prim)),
+				},
+			})
+			r.L(`if err := $0(x, &res); err != nil {`, manualName(sig.name))
+			g.renderWrappedReturn(r, "run user defined conversion")
+			r.L(`}`)
+		}
+
+		shape := g.currentShape()
+		g.renderFuncHead(head, shape, sig)
+		head.L(`    var res $0`, primname)
+
+		r.N()
+		g.renderFuncReturn(r, shape, sig, "&res")
+		r.L(`}`)
+
+		g.renderBatchHelpers(r, shape, sig)
+	}
+
+	return nil
+}
+
+// structToMap генерация кода записи полей структуры src типа t в словарь dst. prefix — путь к вложенной
+// структуре для сообщений. Возвращает true если не все поля удалось записать.
+func (g *Generator) structToMap(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	src string,
+	t *types.Named,
+	prefix string,
+	depth int,
+	visited map[*types.Named]struct{},
+) (mismatch bool) {
+	for _, mf := range g.mapFields(t.Underlying().(*types.Struct)) {
+		f := mf.field
+		path := prefix + f.Name()
+		if depth == 0 {
+			if _, ok := g.xclude[f.Name()]; ok {
+				mismatch = true
+				continue
+			}
+		}
+
+		if nested := g.nestedMapStruct(f.Type(), visited); nested != nil {
+			message.Infof("primary %s(%s) ↔ key %q: nested map", path, f.Type(), mf.key)
+
+			r.N()
+			r.L(`// convert field $0 into nested map under key $1`, f.Name(), mf.key)
+			if isPointer(f.Type()) {
+				r.L(`if $0.$1 != nil {`, src, f.Name())
+			} else {
+				r.L(`{`)
+			}
+
+			nm := fmt.Sprintf("nested%d", depth+1)
+			r.L(`$0 := make($1, $2)`, nm, r.Type(g.secMap), len(g.mapFields(nested.Underlying().(*types.Struct))))
+			visited[nested] = struct{}{}
+			if g.structToMap(r, nm, src+"."+f.Name(), nested, path+".", depth+1, visited) {
+				mismatch = true
+			}
+			delete(visited, nested)
+			r.L(`$0[$1] = $2`, dst, strconv.Quote(mf.key), nm)
+			r.L(`}`)
+			continue
+		}
+
+		descr := g.getFieldTypeMatchDescription(f, f.Type(), g.secMap.Elem())
+		if _, ok := descr.(*FieldMatchNoMatch); ok {
+			message.Warningf("primary field %s (%s): cannot be put into %s", path, f.Type(), g.secMap)
+			mismatch = true
+			continue
+		}
+
+		message.Infof("primary %s(%s) ↔ key %q: %s", path, f.Type(), mf.key, descr)
+		r.N()
+		r.L(`// convert field $0 into key $1`, f.Name(), mf.key)
+		g.convertValue(
+			r,
+			dst+"["+strconv.Quote(mf.key)+"]",
+			g.secMap.Elem(),
+			src+"."+f.Name(),
+			f.Type(),
+			descr,
+			"field "+path,
+			false,
+		)
+	}
+
+	return mismatch
+}
+
+// mapToStruct генерация кода чтения полей структуры dst типа t из словаря src. prefix — путь к вложенной
+// структуре для сообщений. Возвращает true если не все поля удалось прочитать.
+func (g *Generator) mapToStruct(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	src string,
+	t *types.Named,
+	prefix string,
+	depth int,
+	visited map[*types.Named]struct{},
+) (mismatch bool) {
+	elem := g.secMap.Elem()
+	vn := fmt.Sprintf("v%d", depth)
+
+	for _, mf := range g.mapFields(t.Underlying().(*types.Struct)) {
+		f := mf.field
+		path := prefix + f.Name()
+		key := strconv.Quote(mf.key)
+		if depth == 0 {
+			if _, ok := g.xclude[f.Name()]; ok {
+				mismatch = true
+				continue
+			}
+		}
+
+		if nested := g.nestedMapStruct(f.Type(), visited); nested != nil {
+			nm := fmt.Sprintf("nested%d", depth+1)
+			target := fmt.Sprintf("nestedres%d", depth+1)
+
+			r.N()
+			r.L(`// convert nested map under key $0 into field $1`, mf.key, f.Name())
+			r.L(`if $0, ok := $1[$2]; ok {`, vn, src, key)
+			r.L(`$0, ok := $1.($2)`, nm, vn, r.Type(g.secMap))
+			r.L(`if !ok {`)
+			g.renderUnexpectedType(r, vn, path, mf.key)
+			r.L(`}`)
+			r.N()
+			r.L(`var $0 $1`, target, r.Type(nested))
+			visited[nested] = struct{}{}
+			if g.mapToStruct(r, target, nm, nested, path+".", depth+1, visited) {
+				mismatch = true
+			}
+			delete(visited, nested)
+			if isPointer(f.Type()) {
+				r.L(`$0.$1 = &$2`, dst, f.Name(), target)
+			} else {
+				r.L(`$0.$1 = $2`, dst, f.Name(), target)
+			}
+			r.L(`}`)
+			continue
+		}
+
+		if types.IsInterface(elem) {
+			// значения словаря хранятся как интерфейсы, нужна проверка типа
+			want := unpointer(f.Type())
+			if !types.AssignableTo(want, elem) {
+				mismatch = true
+				continue
+			}
+
+			tv := fmt.Sprintf("tv%d", depth)
+			r.N()
+			r.L(`// convert key $0 into field $1`, mf.key, f.Name())
+			r.L(`if $0, ok := $1[$2]; ok {`, vn, src, key)
+			r.L(`$0, ok := $1.($2)`, tv, vn, r.Type(want))
+			r.L(`if !ok {`)
+			g.renderUnexpectedType(r, vn, path, mf.key)
+			r.L(`}`)
+			r.N()
+			g.convertValue(r, dst+"."+f.Name(), f.Type(), tv, want, &FieldMatchDirect{}, "field "+path, false)
+			r.L(`}`)
+			continue
+		}
+
+		descr := g.getFieldTypeMatchDescription(f, elem, f.Type())
+		if _, ok := descr.(*FieldMatchNoMatch); ok {
+			mismatch = true
+			continue
+		}
+
+		r.N()
+		r.L(`// convert key $0 into field $1`, mf.key, f.Name())
+		r.L(`if $0, ok := $1[$2]; ok {`, vn, src, key)
+		g.convertValue(r, dst+"."+f.Name(), f.Type(), vn, elem, descr, "field "+path, false)
+		r.L(`}`)
+	}
+
+	return mismatch
+}
+
+// mapFields поля структуры сохраняемые в словаре. Ключом служит значение тега с ключом tagKey если он задан,
+// либо gogh.Underscored от имени поля. Поля с тегом "-" пропускаются.
+func (g *Generator) mapFields(s *types.Struct) []mapField {
+	var res []mapField
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if f.Name() == "" || !f.Exported() || g.isSkippedField(s, f) {
+			continue
+		}
+
+		key := gogh.Underscored(f.Name())
+		if g.tagKey != "" {
+			value, _ := reflect.StructTag(s.Tag(i)).Lookup(g.tagKey)
+			name, _, _ := strings.Cut(value, ",")
+			switch name {
+			case "-":
+				continue
+			case "":
+			default:
+				key = name
+			}
+		}
+
+		res = append(res, mapField{
+			field: f,
+			key:   key,
+		})
+	}
+
+	return res
+}
+
+// nestedMapStruct возвращает тип структуры если значение данного типа должно сохраняться в словаре вложенным
+// словарём. Для этого значения словаря должны вмещать в себя словари, а структура должна иметь публичные поля
+// и не встречаться выше по вложенности.
+func (g *Generator) nestedMapStruct(t types.Type, visited map[*types.Named]struct{}) *types.Named {
+	if !types.IsInterface(g.secMap.Elem()) || !types.AssignableTo(g.secMap, g.secMap.Elem()) {
+		return nil
+	}
+
+	n, ok := unpointer(t).(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := visited[n]; ok {
+		return nil
+	}
+
+	s, ok := n.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Exported() {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// renderUnexpectedType генерация возврата ошибки несоответствия типа значения под ключом словаря
+func (g *Generator) renderUnexpectedType(r *gogh.GoRenderer[*imports.Imports], value, whoami, key string) {
+	if g.customErrs {
+		r.Imports().Errors().Ref("errors")
+		g.renderErrorReturn(r, `$errors.Newf("field $0: unexpected type %T of key '$1'", $2)`, whoami, key, value)
+	} else {
+		r.Imports().Fmt().Ref("fmt")
+		g.renderErrorReturn(r, `$fmt.Errorf("field $0: unexpected type %T of key '$1'", $2)`, whoami, key, value)
+	}
+}
+
+// renderWrappedReturn генерация возврата обёрнутой ошибки err
+func (g *Generator) renderWrappedReturn(r *gogh.GoRenderer[*imports.Imports], msg string) {
+	if g.customErrs {
+		r.Imports().Errors().Ref("errors")
+		g.renderErrorReturn(r, `$errors.Wrap(err, "$0")`, msg)
+	} else {
+		r.Imports().Fmt().Ref("fmt")
+		g.renderErrorReturn(r, `$fmt.Errorf("$0: %w", err)`, msg)
+	}
+}
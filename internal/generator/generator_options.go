@@ -0,0 +1,163 @@
+package generator
+
+// Option опция генератора
+type Option func(g *Generator)
+
+// Direction направление генерируемых конвертаций
+type Direction string
+
+// Поддерживаемые направления конвертаций
+const (
+	DirectionBoth      Direction = "both"
+	DirectionPrimToSec Direction = "prim-to-sec"
+	DirectionSecToPrim Direction = "sec-to-prim"
+)
+
+// primToSec генерируется конвертация primary → secondary
+func (g *Generator) primToSec() bool {
+	return g.direction != DirectionSecToPrim
+}
+
+// secToPrim генерируется конвертация secondary → primary
+func (g *Generator) secToPrim() bool {
+	return g.direction != DirectionPrimToSec
+}
+
+// WithProtoNames сопоставление полей структур сгенерированных protoc-gen-go по именам полей в protobuf
+// и их json_name вместо имён Go. Помеченные как deprecated поля таких структур исключаются из сопоставления.
+func WithProtoNames() Option {
+	return func(g *Generator) {
+		g.protoNames = true
+	}
+}
+
+// WithTagKey сопоставление полей по значениям тегов с данным ключом (json, db, yaml и т.п.) перед сопоставлением
+// по именам полей.
+func WithTagKey(key string) Option {
+	return func(g *Generator) {
+		g.tagKey = key
+	}
+}
+
+// WithStrconv разрешение конвертаций строк в числа и логические значения и обратно функциями strconv. Без
+// аргументов конвертации разрешаются для всех полей, иначе только для данных полей primary-структуры.
+func WithStrconv(fields ...string) Option {
+	return func(g *Generator) {
+		if len(fields) == 0 {
+			g.strconvAll = true
+			return
+		}
+
+		if g.strconvFields == nil {
+			g.strconvFields = map[string]struct{}{}
+		}
+		for _, f := range fields {
+			g.strconvFields[f] = struct{}{}
+		}
+	}
+}
+
+// WithTimePolicy представление time.Time и time.Duration целыми числами (unix, unix-ms, unix-ns) или строками
+// в данном формате. Без перечисления полей представление применяется ко всем полям, иначе только к данным полям
+// primary-структуры.
+func WithTimePolicy(policy string, fields ...string) Option {
+	return func(g *Generator) {
+		if len(fields) == 0 {
+			g.timeAll = policy
+			return
+		}
+
+		if g.timeFields == nil {
+			g.timeFields = map[string]string{}
+		}
+		for _, f := range fields {
+			g.timeFields[f] = policy
+		}
+	}
+}
+
+// WithSQLFields сопоставление типов, реализующих driver.Valuer и sql.Scanner, у данных полей primary-структуры
+// с любым примитивом driver.Value. Без этого они сопоставляются только с примитивом, на котором построены.
+func WithSQLFields(fields ...string) Option {
+	return func(g *Generator) {
+		if g.sqlFields == nil {
+			g.sqlFields = map[string]struct{}{}
+		}
+		for _, f := range fields {
+			g.sqlFields[f] = struct{}{}
+		}
+	}
+}
+
+// WithDirection генерация конвертаций только в данном направлении. Несопоставленные поля учитываются тоже только
+// для него, так что и расширения пользователя требуются только для этого направления.
+func WithDirection(d Direction) Option {
+	return func(g *Generator) {
+		g.direction = d
+	}
+}
+
+// WithAlwaysErrors функции конвертации имеют сигнатуру func(*X) (*Y, error) даже если ни одна из конвертаций полей
+// не может завершиться ошибкой. Нужно для стабильности API.
+func WithAlwaysErrors() Option {
+	return func(g *Generator) {
+		g.alwaysErrors = true
+	}
+}
+
+// WithByValue функции конвертации, которые не могут завершиться ошибкой, имеют сигнатуру func(X) Y вместо
+// func(*X) *Y.
+func WithByValue() Option {
+	return func(g *Generator) {
+		g.byValue = true
+	}
+}
+
+// WithTests генерация тестов, проверяющих, что значения сопоставленных полей переживают конвертацию туда и обратно.
+func WithTests() Option {
+	return func(g *Generator) {
+		g.tests = true
+	}
+}
+
+// WithBatchHelpers генерация рядом с каждой функцией конвертации функций XSliceToYSlice и XMapToYMap,
+// конвертирующих срезы и словари значений.
+func WithBatchHelpers() Option {
+	return func(g *Generator) {
+		g.batch = true
+	}
+}
+
+// WithOutputPackage генерация кода в пакет с данным путём вместо пакета primary-структуры. Пакет должен находиться
+// в текущем модуле, методы в таком случае не генерируются.
+func WithOutputPackage(path string) Option {
+	return func(g *Generator) {
+		g.outPath = path
+	}
+}
+
+// WithTypedFileName имя файла сгенерированного кода включает имя primary-типа. Нужно, когда primary-структуры
+// нескольких пар определены в одном файле и их конвертации иначе были бы записаны в один и тот же файл.
+func WithTypedFileName() Option {
+	return func(g *Generator) {
+		g.typedFileName = true
+	}
+}
+
+// WithFieldMapping сопоставление поля prim primary-структуры полю sec secondary-структуры независимо от их имён
+// и тегов.
+func WithFieldMapping(prim, sec string) Option {
+	return func(g *Generator) {
+		if g.mappings == nil {
+			g.mappings = map[string]string{}
+		}
+		g.mappings[prim] = sec
+	}
+}
+
+// WithPackages поиск структур в уже загруженных пакетах вместо их загрузки. Пакеты структур должны быть среди них.
+func WithPackages(pkgs *Packages) Option {
+	return func(g *Generator) {
+		g.loaded = pkgs
+	}
+}
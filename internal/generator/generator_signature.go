@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// funcShape форма сигнатуры генерируемой функции конвертации
+type funcShape int
+
+const (
+	// shapeFallible func(x *X) (*Y, error)
+	shapeFallible funcShape = iota
+	// shapePointer func(x *X) *Y
+	shapePointer
+	// shapeValue func(x X) Y
+	shapeValue
+)
+
+// funcSignature описание генерируемой функции конвертации. Структуры передаются и возвращаются по указателю,
+// кроме формы shapeValue, словари — как есть.
+type funcSignature struct {
+	// method имя метода источника, пустое для функций
+	method    string
+	name      string
+	src       string
+	srcStruct bool
+	dst       string
+	dstStruct bool
+	// slices и maps имена функций пакетной конвертации срезов и словарей
+	slices string
+	maps   string
+}
+
+// types типы аргумента и результата функции формы shape
+func (s funcSignature) types(shape funcShape) (param string, result string) {
+	param = s.src
+	if s.srcStruct && shape != shapeValue {
+		param = "*" + param
+	}
+
+	result = s.dst
+	if s.dstStruct && shape != shapeValue {
+		result = "*" + result
+	}
+
+	return param, result
+}
+
+// currentShape форма сигнатуры функции, тело которой только что отрисовано. Функции, которые не могут завершиться
+// ошибкой, её и не возвращают, если только старая форма сигнатуры не задана явно.
+func (g *Generator) currentShape() funcShape {
+	switch {
+	case g.fallible || g.alwaysErrors:
+		return shapeFallible
+	case g.byValue:
+		return shapeValue
+	default:
+		return shapePointer
+	}
+}
+
+// renderFuncHead отрисовка документации, сигнатуры и проверки аргумента на nil. Отрисовывается в отложенный
+// renderer после тела функции, когда уже известна её форма.
+func (g *Generator) renderFuncHead(head *gogh.GoRenderer[*imports.Imports], shape funcShape, sig funcSignature) {
+	param, result := sig.types(shape)
+	if shape == shapeFallible {
+		result = "(" + result + ", error)"
+	}
+
+	if sig.method != "" {
+		head.L(`// $0 conversion of $1 into $2`, sig.method, sig.src, sig.dst)
+		head.L(`func (x $0) $1() $2 {`, param, sig.method, result)
+	} else {
+		head.L(`// $0 conversion of $1 into $2`, sig.name, sig.src, sig.dst)
+		head.L(`func $0(x $1) $2 {`, sig.name, param, result)
+	}
+
+	// nil в аргументе даёт nil в результате если обе стороны могут быть nil
+	if strings.HasPrefix(param, "*") || !sig.srcStruct && (shape != shapeValue || !sig.dstStruct) {
+		head.L(`    if x == nil {`)
+		if shape == shapeFallible {
+			head.L(`        return nil, nil`)
+		} else {
+			head.L(`        return nil`)
+		}
+		head.L(`    }`)
+		head.N()
+	}
+}
+
+// renderFuncReturn отрисовка возврата результата. ref — выражение указателя на результат-структуру либо
+// сам результат-словарь.
+func (g *Generator) renderFuncReturn(r *gogh.GoRenderer[*imports.Imports], shape funcShape, sig funcSignature, ref string) {
+	switch {
+	case shape == shapeFallible:
+		r.L(`    return $0, nil`, ref)
+	case shape == shapeValue && sig.dstStruct && strings.HasPrefix(ref, "&"):
+		r.L(`    return $0`, strings.TrimPrefix(ref, "&"))
+	case shape == shapeValue && sig.dstStruct:
+		r.L(`    return *$0`, ref)
+	default:
+		r.L(`    return $0`, ref)
+	}
+}
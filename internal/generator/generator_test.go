@@ -0,0 +1,360 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"go/types"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/diff"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files with generated code")
+
+// goldenModule путь модуля, в который копируются исходники тестового случая
+const goldenModule = "example.com/fix"
+
+// goldenPair пара конвертируемых типов тестового случая. Типы задаются в виде <пакет>:<имя> с путём пакета
+// относительно корня модуля, secondary также может быть литералом словаря.
+type goldenPair struct {
+	prim   string
+	sec    string
+	method string
+	xclude []string
+	opts   []Option
+}
+
+// TestGenerate генерация конвертаций для исходников из testdata/golden/<случай>. Сгенерированный код сравнивается
+// с файлами <файл>.golden рядом с исходниками (go test -update перезаписывает их) и должен собираться вместе
+// с ними, а сгенерированные тесты — проходить.
+func TestGenerate(t *testing.T) {
+	// golang.org/x/tools v0.1.9 передаёт go/types размеры только в виде *types.StdSizes, которыми они перестали
+	// быть в Go 1.21, и падает при проверке типов
+	if _, ok := types.SizesFor("gc", runtime.GOARCH).(*types.StdSizes); !ok {
+		t.Skipf("golang.org/x/tools cannot load packages with %s", runtime.Version())
+	}
+
+	tests := []struct {
+		name  string
+		pairs []goldenPair
+	}{
+		{
+			name: "gogo",
+			pairs: []goldenPair{
+				{prim: "model:Address", sec: "pb:Address"},
+				{prim: "model:Event", sec: "pb:Event"},
+			},
+		},
+		{
+			name: "protonames",
+			pairs: []goldenPair{
+				{prim: "model:Profile", sec: "pb:Profile", opts: []Option{WithProtoNames()}},
+			},
+		},
+		{
+			name: "tags",
+			pairs: []goldenPair{
+				{prim: "model:Item", sec: "dto:Item", opts: []Option{WithTagKey("json")}},
+			},
+		},
+		{
+			name: "union",
+			pairs: []goldenPair{
+				{prim: "domain:Event", sec: "thr:Event"},
+			},
+		},
+		{
+			name: "map",
+			pairs: []goldenPair{
+				{prim: "mp:Person", sec: "mp:Payload", opts: []Option{WithTagKey("json")}},
+				{prim: "mp:Flat", sec: "map[string]string"},
+			},
+		},
+		{
+			name: "text",
+			pairs: []goldenPair{
+				{prim: "txt:Model", sec: "txt:DTO"},
+			},
+		},
+		{
+			name: "sql",
+			pairs: []goldenPair{
+				{prim: "sq:User", sec: "sq:Row"},
+				{prim: "sq:Acc", sec: "sq:AccRow", opts: []Option{WithSQLFields("Name", "Mis")}},
+			},
+		},
+		{
+			name: "bytes",
+			pairs: []goldenPair{
+				{prim: "bt:Domain", sec: "bt:Proto"},
+			},
+		},
+		{
+			name: "strconv",
+			pairs: []goldenPair{
+				{
+					prim: "sc:Form",
+					sec:  "sc:Query",
+					opts: []Option{WithStrconv("Age", "Score", "Active", "Level", "Count", "Ids")},
+				},
+			},
+		},
+		{
+			name: "time",
+			pairs: []goldenPair{
+				{
+					prim: "tp:Event",
+					sec:  "tp:Row",
+					opts: []Option{
+						WithTimePolicy("rfc3339"),
+						WithTimePolicy("unix-ms", "At"),
+						WithTimePolicy("2006-01-02", "Day"),
+						WithTimePolicy("unix", "TTL", "Born"),
+						WithTimePolicy("unix-ns", "Nano"),
+					},
+				},
+			},
+		},
+		{
+			name: "newtype",
+			pairs: []goldenPair{
+				{prim: "nt:Domain", sec: "nt:Wire"},
+			},
+		},
+		{
+			name: "valueobject",
+			pairs: []goldenPair{
+				{prim: "vo:Account", sec: "vo:AccountDTO"},
+				{prim: "vo:Point", sec: "vo:PointDTO", opts: []Option{WithByValue()}},
+				{prim: "vo:User", sec: "vo:UserDTO"},
+			},
+		},
+		{
+			name: "typedfile",
+			pairs: []goldenPair{
+				{prim: "vo:Point", sec: "vo:PointDTO", opts: []Option{WithTypedFileName()}},
+				{prim: "vo:Size", sec: "vo:SizeDTO", opts: []Option{WithTypedFileName()}},
+			},
+		},
+		{
+			name: "batch",
+			pairs: []goldenPair{
+				{prim: "bh:News", sec: "bh:NewsRow", opts: []Option{WithBatchHelpers(), WithTimePolicy("rfc3339")}},
+				{prim: "bh:Address", sec: "bh:AddressDTO", opts: []Option{WithBatchHelpers(), WithTypedFileName()}},
+				{prim: "bh:User", sec: "bh:UserDTO", opts: []Option{WithBatchHelpers()}},
+			},
+		},
+		{
+			name: "hooks",
+			pairs: []goldenPair{
+				{prim: "hk:User", sec: "hk:UserDTO"},
+			},
+		},
+		{
+			name: "roundtrip",
+			pairs: []goldenPair{
+				{prim: "rt:Order", sec: "rt:OrderRow", opts: []Option{WithTests()}},
+			},
+		},
+		{
+			name: "methods",
+			pairs: []goldenPair{
+				{prim: "mt:Person", sec: "mt:PersonDTO"},
+			},
+		},
+		{
+			name: "setters",
+			pairs: []goldenPair{
+				{prim: "st:Order", sec: "st/model:OrderModel"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			testGolden(t, filepath.Join("testdata", "golden", tt.name), tt.pairs)
+		})
+	}
+}
+
+// testGolden генерация пар pairs в копии исходников из src и сверка результата с эталонами
+func testGolden(t *testing.T, src string, pairs []goldenPair) {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modules, err := filepath.Abs(filepath.Join("testdata", "modules"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	copyGoldenSources(t, src, dir)
+	gomod := "module " + goldenModule + "\n\ngo 1.18\n\n" +
+		"require github.com/gogo/protobuf v1.3.2\n\n" +
+		"replace github.com/gogo/protobuf => " + filepath.Join(modules, "gogo") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// gogh и загрузка пакетов работают с модулем текущего каталога
+	chdir(t, dir)
+
+	// код форматируется go/format, чтобы эталоны не зависели от установленного fancyfmt
+	generated := map[string]struct{}{}
+	for _, p := range pairs {
+		g := newGoldenGenerator(t, p)
+		prj, err := gogh.New[*imports.Imports](format.Source, imports.New(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := g.Generate(prj); err != nil {
+			t.Fatalf("generate %s ↔ %s: %s", p.prim, p.sec, err)
+		}
+		if err := prj.Render(); err != nil {
+			t.Fatalf("render %s ↔ %s: %s", p.prim, p.sec, err)
+		}
+
+		for _, file := range g.Files() {
+			generated[file] = struct{}{}
+		}
+	}
+
+	for file := range generated {
+		got, err := os.ReadFile(filepath.Join(dir, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		golden := filepath.Join(src, file+".golden")
+		if *updateGolden {
+			if err := os.WriteFile(golden, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Errorf("read golden file of %s: %s", file, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs from the golden file:\n%s", file, diff.Unified("a/"+file, "b/"+file, want, got))
+		}
+	}
+
+	// эталоны без сгенерированных файлов остаются от старых версий генератора
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".golden") {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, strings.TrimSuffix(path, ".golden"))
+		if err != nil {
+			return err
+		}
+		if _, ok := generated[filepath.ToSlash(rel)]; !ok {
+			t.Errorf("golden file %s has no generated counterpart", rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runGo(t, "vet", "./...")
+	runGo(t, "test", "./...")
+}
+
+// newGoldenGenerator генератор пары тестового случая
+func newGoldenGenerator(t *testing.T, p goldenPair) *Generator {
+	primPkg, primName, ok := strings.Cut(p.prim, ":")
+	if !ok {
+		t.Fatalf("primary must look like <pkg>:<name>, got '%s'", p.prim)
+	}
+
+	var secPkg string
+	secName := p.sec
+	if pkg, name, ok := strings.Cut(p.sec, ":"); ok {
+		secPkg = goldenModule + "/" + pkg
+		secName = name
+	}
+
+	g, err := New(goldenModule+"/"+primPkg, primName, secPkg, secName, p.method, false, p.xclude, p.opts...)
+	if err != nil {
+		t.Fatalf("setup generator of %s ↔ %s: %s", p.prim, p.sec, err)
+	}
+
+	return g
+}
+
+// copyGoldenSources копирование исходников тестового случая из src в dst без эталонов
+func copyGoldenSources(t *testing.T, src, dst string) {
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, 0755)
+		case strings.HasSuffix(path, ".golden"):
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, data, 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// chdir смена текущего каталога на время теста
+func chdir(t *testing.T, dir string) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// runGo запуск команды go в текущем каталоге, тест проваливается если она завершилась с ошибкой
+func runGo(t *testing.T, args ...string) {
+	cmd := exec.Command("go", args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Errorf("go %s: %s\n%s", strings.Join(args, " "), err, out)
+	}
+}
@@ -0,0 +1,283 @@
+package generator
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/message"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// generatedFunc сгенерированная функция конвертации и форма её сигнатуры
+type generatedFunc struct {
+	sig   funcSignature
+	shape funcShape
+}
+
+// roundTripField поле primary-структуры, значение которого проверяется тестом конвертации туда и обратно
+type roundTripField struct {
+	field *types.Var
+	// enum значения перечисления, из которых выбирается значение поля, пусто если поле не является перечислением
+	enum []string
+}
+
+// roundTripFields поля primary-структуры, значения которых проверяются тестом конвертации туда и обратно. Поля,
+// для которых вызываются функции пользователя, и поля, значения которых могут не пережить конвертацию, не
+// проверяются и остаются нулевыми. Пустой список, если тест не может быть сгенерирован, в т.ч. если нулевое
+// значение какого-то из непроверяемых полей может не сконвертироваться.
+func (g *Generator) roundTripFields(matches []fieldMatchInfo) []roundTripField {
+	if g.primToSecFunc == nil || g.secToPrimFunc == nil {
+		message.Warning("round trip test requires conversions in both directions, will not generate it")
+		return nil
+	}
+	if g.primVO != nil || g.secVO != nil {
+		message.Warning("values of value objects cannot be made up, will not generate round trip test")
+		return nil
+	}
+
+	var res []roundTripField
+	for _, m := range matches {
+		if m.sec == nil {
+			continue
+		}
+		if _, ok := g.xclude[m.prim.Name()]; ok {
+			continue
+		}
+
+		if f, ok := g.roundTripField(m); ok {
+			res = append(res, f)
+			continue
+		}
+
+		// nil конвертируется без ошибок, прочие нулевые значения могут оказаться недопустимыми
+		_, there := g.primToSecFallible[m.prim]
+		_, back := g.secToPrimFallible[m.prim]
+		if (there || back) && !(isNilable(m.prim.Type()) && isNilable(m.sec.Type())) {
+			message.Warningf(
+				"conversion of field %s may fail and there is no way to make up its value, will not generate round trip test",
+				m.prim.Name(),
+			)
+			return nil
+		}
+	}
+	if len(res) == 0 {
+		message.Warning("no fields to check in round trip test, will not generate it")
+	}
+
+	return res
+}
+
+// roundTripField выяснение, что значение поля сопоставления m может быть придумано и должно пережить конвертацию
+// туда и обратно
+func (g *Generator) roundTripField(m fieldMatchInfo) (roundTripField, bool) {
+	switch v := m.descr.(type) {
+	case *FieldMatchDirect, *FieldMatchCastable, *FieldMatchBytes:
+	case *FieldMatchConversion:
+		// функции преобразования могут принимать не любые значения
+		if _, ok := g.primToSecFallible[m.prim]; ok {
+			return roundTripField{}, false
+		}
+	case *FieldMatchEnum:
+		if _, ok := m.prim.Type().(*types.Pointer); ok {
+			return roundTripField{}, false
+		}
+
+		values := enumRoundTripValues(v)
+		if len(values) == 0 {
+			return roundTripField{}, false
+		}
+
+		return roundTripField{
+			field: m.prim,
+			enum:  values,
+		}, true
+	default:
+		return roundTripField{}, false
+	}
+
+	if !isRandomizable(m.prim.Type()) {
+		return roundTripField{}, false
+	}
+
+	return roundTripField{field: m.prim}, true
+}
+
+// enumRoundTripValues значения primary-перечисления, которые есть и в secondary-перечислении
+func enumRoundTripValues(e *FieldMatchEnum) []string {
+	secValues := map[string]struct{}{}
+	for _, v := range e.Secondary.values {
+		secValues[v.Val().ExactString()] = struct{}{}
+	}
+
+	var res []string
+	seen := map[string]struct{}{}
+	for _, v := range e.Primary.values {
+		value := v.Val().ExactString()
+		if _, ok := secValues[value]; !ok {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+
+		seen[value] = struct{}{}
+		res = append(res, value)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// isNilable нулевым значением типа является nil
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Slice, *types.Map, *types.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// renderRoundTripTest генерация fuzz-теста: primary-значение со случайными значениями полей fields конвертируется
+// в secondary и обратно, значения этих полей должны сохраниться.
+func (g *Generator) renderRoundTripTest(r *gogh.GoRenderer[*imports.Imports], fields []roundTripField) {
+	there := g.primToSecFunc
+	back := g.secToPrimFunc
+
+	r.Imports().Add("math/rand").Ref("rand")
+	r.Imports().Add("reflect").Ref("reflect")
+	r.Imports().Add("testing").Ref("testing")
+
+	name := r.S("Fuzz${0|P}RoundTrip", there.sig.name)
+	r.L(`// $0 checks matched fields of $1 survive conversion into $2 and back`, name, there.sig.src, there.sig.dst)
+	r.L(`func $0(f *$testing.F) {`, name)
+	r.L(`    f.Add(int64(0))`)
+	r.L(`    f.Add(int64(1))`)
+	r.L(`    f.Fuzz(func(t *$testing.T, seed int64) {`)
+	r.L(`        rnd := $rand.New($rand.NewSource(seed))`)
+	r.N()
+	r.L(`        var x $0`, g.primName(r))
+	for _, f := range fields {
+		if len(f.enum) > 0 {
+			r.L(`        x.$0 = $1`, f.field.Name(), g.randomEnumValue(r, f.field.Type(), f.enum))
+			continue
+		}
+
+		r.L(`        x.$0 = $1`, f.field.Name(), g.randomValue(r, f.field.Type()))
+	}
+	r.N()
+
+	arg := "&x"
+	if there.shape == shapeValue {
+		arg = "x"
+	}
+	g.renderTestCall(r, there, "y", arg)
+
+	switch {
+	case there.shape == shapeValue && back.shape != shapeValue:
+		arg = "&y"
+	case there.shape != shapeValue && back.shape == shapeValue:
+		arg = "*y"
+	default:
+		arg = "y"
+	}
+	g.renderTestCall(r, back, "res", arg)
+
+	for _, f := range fields {
+		r.N()
+		r.L(`        if !$reflect.DeepEqual(x.$0, res.$0) {`, f.field.Name())
+		r.L(`            t.Errorf("field $0: got %v after round trip, want %v", res.$0, x.$0)`, f.field.Name())
+		r.L(`        }`)
+	}
+	r.L(`    })`)
+	r.L(`}`)
+}
+
+// renderTestCall генерация вызова функции конвертации f от arg с записью результата в dst
+func (g *Generator) renderTestCall(r *gogh.GoRenderer[*imports.Imports], f *generatedFunc, dst, arg string) {
+	call := r.S("$0($1)", f.sig.name, arg)
+	if f.sig.method != "" {
+		call = r.S("x.$0()", f.sig.method)
+	}
+
+	if f.shape != shapeFallible {
+		r.L(`        $0 := $1`, dst, call)
+		return
+	}
+
+	r.L(`        $0, err := $1`, dst, call)
+	r.L(`        if err != nil {`)
+	r.L(`            t.Fatalf("convert $0 into $1: %v", err)`, f.sig.src, f.sig.dst)
+	r.L(`        }`)
+	r.N()
+}
+
+// isRandomizable выяснение, что для типа t генерируются случайные значения: это логические значения, числа,
+// строки, срезы байт и time.Time
+func isRandomizable(t types.Type) bool {
+	if duration, ok := isTimeType(t); ok && !duration {
+		return true
+	}
+
+	switch v := t.Underlying().(type) {
+	case *types.Basic:
+		return v.Info()&(types.IsBoolean|types.IsInteger|types.IsFloat|types.IsString) != 0
+	case *types.Slice:
+		b, ok := v.Elem().(*types.Basic)
+		return ok && b.Kind() == types.Byte
+	default:
+		return false
+	}
+}
+
+// randomValue выражение случайного значения типа t, получаемого из генератора rnd. Числа выбираются так, чтобы
+// они были представимы в любом числовом типе.
+func (g *Generator) randomValue(r *gogh.GoRenderer[*imports.Imports], t types.Type) string {
+	if duration, ok := isTimeType(t); ok && !duration {
+		r.Imports().Add("time").Ref("time")
+		return r.S("$time.Unix(rnd.Int63n(1<<32), 0).UTC()")
+	}
+
+	// значения неименованных строк, срезов байт и логических значений приводить не нужно
+	cast := func(value string) string {
+		if _, ok := t.(*types.Named); ok {
+			return r.S("$0($1)", r.Type(t), value)
+		}
+
+		return value
+	}
+
+	switch v := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case v.Info()&types.IsBoolean != 0:
+			return cast("rnd.Intn(2) == 1")
+		case v.Info()&types.IsInteger != 0:
+			return r.S("$0(rnd.Intn(100))", r.Type(t))
+		case v.Info()&types.IsFloat != 0:
+			return r.S("$0(rnd.Intn(1000)) / 4", r.Type(t))
+		case v.Info()&types.IsString != 0:
+			r.Imports().Add("strconv").Ref("strconv")
+			return cast(r.S("$strconv.FormatInt(rnd.Int63(), 36)"))
+		}
+
+	case *types.Slice:
+		r.Imports().Add("strconv").Ref("strconv")
+		return cast(r.S("$strconv.AppendInt(nil, rnd.Int63(), 36)"))
+	}
+
+	return ""
+}
+
+// randomEnumValue выражение случайного значения перечисления типа t из значений values
+func (g *Generator) randomEnumValue(r *gogh.GoRenderer[*imports.Imports], t types.Type, values []string) string {
+	typ := r.Type(t)
+	items := make([]string, len(values))
+	for i, v := range values {
+		items[i] = typ + "(" + v + ")"
+	}
+
+	return r.S("[]$0{$1}[rnd.Intn($2)]", typ, strings.Join(items, ", "), len(values))
+}
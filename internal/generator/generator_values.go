@@ -68,15 +68,15 @@ func (g *Generator) convertValue(
 				r.L(`if err != nil {`)
 				if g.customErrs {
 					r.Imports().Errors().Ref("errors")
-					r.L(
-						`    return nil, $errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
+					g.renderErrorReturn(r,
+						`$errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
 						whoami,
 						humanGuess(src),
 						src,
 					)
 				} else {
 					r.Imports().Fmt().Ref("fmt")
-					r.L(`    return nil, fmt.Errorf("convert $0: %w", err)`, whoami)
+					g.renderErrorReturn(r, `fmt.Errorf("convert $0: %w", err)`, whoami)
 				}
 				r.L(`}`)
 				r.N()
@@ -88,15 +88,15 @@ func (g *Generator) convertValue(
 				r.L(`} else {`)
 				if g.customErrs {
 					r.Imports().Errors().Ref("errors")
-					r.L(
-						`    return nil, $errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
+					g.renderErrorReturn(r,
+						`$errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`,
 						whoami,
 						humanGuess(src),
 						src,
 					)
 				} else {
 					r.Imports().Fmt().Ref("fmt")
-					r.L(`    return nil, $fmt.Errorf("convert $0: %w", err)`, whoami)
+					g.renderErrorReturn(r, `$fmt.Errorf("convert $0: %w", err)`, whoami)
 				}
 				r.L(`}`)
 			}
@@ -105,15 +105,23 @@ func (g *Generator) convertValue(
 	case *FieldMatchEnum:
 
 		if v.Secondary.isProto {
-			r.L(`if enumval, ok := $0_value[int32($1)]; ok {`, r.Type(dstType), deref(src, srcType))
-			assign(r, dst, dstType, "enumval", srcType)
+			// значения перечисления protobuf проверяются по словарю их имён
+			r.L(`if _, ok := $0_name[int32($1)]; ok {`, r.Type(unpointer(dstType)), deref(src, srcType))
+			assignSafe(
+				r,
+				dst,
+				dstType,
+				r.S("$0($1)", r.Type(unpointer(dstType)), deref(src, srcType)),
+				unpointer(dstType),
+				true,
+			)
 			r.L(`} else {`)
 			if g.customErrs {
 				r.Imports().Errors().Ref("errors")
-				r.L(`    return $errors.Newf("unknown value %v of $0", $1)`, src, deref(src, srcType))
+				g.renderErrorReturn(r, `$errors.Newf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
 			} else {
 				r.Imports().Fmt().Ref("fmt")
-				r.L(`    return $fmt.Errorf("unknown value %v of $0: %w", $1, err)`, src, deref(src, srcType))
+				g.renderErrorReturn(r, `$fmt.Errorf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
 			}
 			r.L(`}`)
 		} else {
@@ -132,14 +140,10 @@ func (g *Generator) convertValue(
 			r.L(`default:`)
 			if g.customErrs {
 				r.Imports().Errors().Ref("errors")
-				r.L(`    return nil, $errors.Newf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
+				g.renderErrorReturn(r, `$errors.Newf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
 			} else {
 				r.Imports().Fmt().Ref("fmt")
-				r.L(
-					`    return nil, $fmt.Errorf("unkown value %v of $0: %w", $1, err)`,
-					whoami,
-					deref(src, srcType),
-				)
+				g.renderErrorReturn(r, `$fmt.Errorf("unknown value %v of $0", $1)`, whoami, deref(src, srcType))
 			}
 			r.L(`}`)
 		}
@@ -214,6 +218,69 @@ func (g *Generator) convertValue(
 		if !nilGuarded && isPointer(dstType) {
 			r.L(`}`)
 		}
+
+	case *FieldMatchUnion:
+		g.convertUnion(r, dst, dstType, src, srcType, v, whoami)
+
+	case *FieldMatchText:
+		g.convertText(r, dst, dstType, src, srcType, v, whoami, nilGuarded)
+
+	case *FieldMatchSQL:
+		g.convertSQL(r, dst, dstType, src, srcType, v, whoami, nilGuarded)
+
+	case *FieldMatchBytes:
+		g.convertBytes(r, dst, dstType, src, srcType, whoami, nilGuarded)
+
+	case *FieldMatchStrconv:
+		g.convertStrconv(r, dst, dstType, src, srcType, v, whoami, nilGuarded)
+
+	case *FieldMatchTime:
+		g.convertTime(r, dst, dstType, src, srcType, v, whoami, nilGuarded)
+
+	case *FieldMatchWrapper:
+		if !v.Secondary {
+			// обёртка является источником, значение берётся прямо из её поля либо методом доступа
+			value := src + "." + v.Value.Name()
+			if v.Accessor != nil {
+				value = src + "." + v.Accessor.Name() + "()"
+			}
+			g.convertValue(r, dst, dstType, value, v.Value.Type(), v.Elem, whoami, false)
+			break
+		}
+
+		// обёртка является приёмником, собираем её во временной переменной
+		if !nilGuarded {
+			r.L(`{`)
+		}
+
+		if v.Constructor == nil {
+			r.L(`var wrapped $0`, r.Type(unpointer(dstType)))
+			g.convertValue(r, "wrapped."+v.Value.Name(), v.Value.Type(), src, srcType, v.Elem, whoami, true)
+			if isPointer(dstType) {
+				r.L(`$0 = &wrapped`, dst)
+			} else {
+				r.L(`$0 = wrapped`, dst)
+			}
+		} else {
+			// значение собирается отдельно и передаётся конструктору обёртки
+			sig := v.Constructor.Type().(*types.Signature)
+			r.L(`var value $0`, r.Type(v.Value.Type()))
+			g.convertValue(r, "value", v.Value.Type(), src, srcType, v.Elem, whoami, true)
+			call := r.S("$0(value)", g.callName(r, v.Constructor))
+			if sig.Results().Len() == 2 {
+				r.L(`if wrapped, err := $0; err == nil {`, call)
+				assign(r, dst, dstType, "wrapped", sig.Results().At(0).Type())
+				r.L(`} else {`)
+				g.renderConversionError(r, whoami, src)
+				r.L(`}`)
+			} else {
+				assignSafe(r, dst, dstType, call, sig.Results().At(0).Type(), true)
+			}
+		}
+
+		if !nilGuarded {
+			r.L(`}`)
+		}
 	}
 
 	if nilGuarded {
@@ -221,8 +288,38 @@ func (g *Generator) convertValue(
 	}
 }
 
+// convertFieldValue конвертация значения поля field структуры x типа owner в приёмник dst. Для структур
+// сгенерированных Thrift наличие значения поля проверяется методом IsSet<Field>, а не сравнением с nil.
+func (g *Generator) convertFieldValue(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	owner types.Type,
+	field *types.Var,
+	descr FieldMatchDescription,
+	whoami string,
+) {
+	if vo := g.valueObjectOf(owner); vo != nil {
+		g.convertValueObjectField(r, dst, dstType, vo, field, descr, whoami)
+		return
+	}
+
+	src := "x." + field.Name()
+	nilable := is[*types.Pointer](field.Type()) || is[*types.Slice](field.Type()) || is[*types.Map](field.Type())
+
+	isset := thriftIsSet(owner, field)
+	if isset == "" || !nilable {
+		g.convertValue(r, dst, dstType, src, field.Type(), descr, whoami, false)
+		return
+	}
+
+	r.L(`if x.$0() {`, isset)
+	g.convertValue(r, dst, dstType, src, field.Type(), descr, whoami, true)
+	r.L(`}`)
+}
+
 func (g *Generator) callName(r *gogh.GoRenderer[*imports.Imports], fn *types.Func) string {
-	if g.prim.Obj().Pkg().Path() == fn.Pkg().Path() {
+	if g.outPath == fn.Pkg().Path() {
 		return fn.Name()
 	}
 
@@ -332,6 +429,19 @@ func unpointer(t types.Type) types.Type {
 	return t
 }
 
+// unalias снятие псевдонимов вроде any, которые go/types начиная с Go 1.22 представляет отдельными типами.
+// types.Unalias в Go 1.18 ещё нет, поэтому псевдоним опознаётся по методу Rhs.
+func unalias(t types.Type) types.Type {
+	for {
+		a, ok := t.(interface{ Rhs() types.Type })
+		if !ok {
+			return t
+		}
+
+		t = a.Rhs()
+	}
+}
+
 func basicZero(t types.Type) string {
 	switch v := t.(type) {
 	case *types.Pointer:
@@ -365,3 +475,21 @@ func humanGuess(src string) string {
 
 	return gogh.Striked(after)
 }
+
+// trackFallible генерация кода функцией render с выяснением, может ли этот код завершиться ошибкой
+func (g *Generator) trackFallible(render func()) bool {
+	fallible := g.fallible
+	g.fallible = false
+	render()
+	res := g.fallible
+	g.fallible = g.fallible || fallible
+
+	return res
+}
+
+// renderErrorReturn возврат ошибки из генерируемой функции. Функция отмечается как способная завершиться ошибкой,
+// от этого зависит её сигнатура.
+func (g *Generator) renderErrorReturn(r *gogh.GoRenderer[*imports.Imports], line string, a ...interface{}) {
+	g.fallible = true
+	r.L(`    return nil, `+line, a...)
+}
@@ -0,0 +1,67 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertBytes копирование значения байтового типа в другой байтовый тип
+func (g *Generator) convertBytes(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	whoami string,
+	nilGuarded bool,
+) {
+	dstUnder := unpointer(dstType)
+	switch byteLikeKind(dstUnder) {
+	case byteLikeSlice:
+		if byteLikeKind(unpointer(srcType)) == byteLikeArray {
+			// срез массива копируется, чтобы приёмник не разделял память с источником
+			r.L(`$0 = append($1(nil), $2[:]...)`, dst, r.Type(dstUnder), src)
+			return
+		}
+
+		fallthrough
+
+	case byteLikeString:
+		assignSafe(r, dst, dstType, r.S("$0($1)", r.Type(dstUnder), deref(src, srcType)), dstUnder, nilGuarded)
+
+	case byteLikeArray:
+		size := dstUnder.Underlying().(*types.Array).Len()
+		r.L(`if len($0) != $1 {`, src, size)
+		if g.customErrs {
+			r.Imports().Errors().Ref("errors")
+			g.renderErrorReturn(r, `$errors.Newf("convert $0: got %d bytes, $1 required", len($2))`, whoami, size, src)
+		} else {
+			r.Imports().Fmt().Ref("fmt")
+			g.renderErrorReturn(r, `$fmt.Errorf("convert $0: got %d bytes, $1 required", len($2))`, whoami, size, src)
+		}
+		r.L(`}`)
+		r.N()
+
+		if !nilGuarded {
+			r.L(`{`)
+		}
+		r.L(`var arr $0`, byteArrayType(r, dstUnder))
+		r.L(`copy(arr[:], $0)`, src)
+		assign(r, dst, dstType, "arr", dstUnder)
+		if !nilGuarded {
+			r.L(`}`)
+		}
+	}
+}
+
+// byteArrayType название типа массива байт: gogh не умеет отрисовывать безымянные массивы
+func byteArrayType(r *gogh.GoRenderer[*imports.Imports], t types.Type) string {
+	if v, ok := t.(*types.Array); ok {
+		return fmt.Sprintf("[%d]%s", v.Len(), r.Type(v.Elem()))
+	}
+
+	return r.Type(t)
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertMethodValue конвертация значения возвращаемого методом источника без параметров. Ошибка метода, если
+// он её возвращает, прерывает конвертацию.
+func (g *Generator) convertMethodValue(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	method *types.Func,
+	descr FieldMatchDescription,
+	whoami string,
+) {
+	src := gogh.Private(gogh.Underscored(method.Name()), "value")
+	sig := method.Type().(*types.Signature)
+
+	r.L(`{`)
+	if sig.Results().Len() == 2 {
+		r.L(`$0, err := x.$1()`, src, method.Name())
+		r.L(`if err != nil {`)
+		g.renderWrappedReturn(r, "call method "+method.Name())
+		r.L(`}`)
+	} else {
+		r.L(`$0 := x.$1()`, src, method.Name())
+	}
+	g.convertValue(r, dst, dstType, src, sig.Results().At(0).Type(), descr, whoami, false)
+	r.L(`}`)
+}
+
+// convertMethodsValues заполнение полей приёмника значениями методов источника и передача значений полей
+// источника сеттерам приёмника
+func (g *Generator) convertMethodsValues(
+	r *gogh.GoRenderer[*imports.Imports],
+	methods []fieldMethodMatch,
+	intoSecondary bool,
+) {
+	for _, m := range methods {
+		if m.intoSecondary() != intoSecondary {
+			continue
+		}
+
+		src, dst := types.Type(g.prim), types.Type(g.sec)
+		descr := m.descr
+		if !intoSecondary {
+			src, dst = dst, src
+			descr = reflectDescr(descr)
+		}
+
+		r.N()
+		if m.setter {
+			r.L(`// convert field $0 with setter $1`, m.field.Name(), m.method.Name())
+			g.convertIntoSetter(r, m.method, func(dst string, dstType types.Type) {
+				g.convertFieldValue(r, dst, dstType, src, m.field, descr, "field "+m.field.Name())
+			})
+			continue
+		}
+
+		r.L(`// convert method $0 into field $1`, m.method.Name(), m.field.Name())
+		g.convertIntoField(r, dst, m.field, func(dst string, dstType types.Type) {
+			g.convertMethodValue(r, dst, dstType, m.method, descr, "method "+m.method.Name())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertIntoField запись значения в поле field результата типа owner: через сеттер поля, если он есть,
+// иначе прямым присваиванием. Само значение отрисовывается функцией convert.
+func (g *Generator) convertIntoField(
+	r *gogh.GoRenderer[*imports.Imports],
+	owner types.Type,
+	field *types.Var,
+	convert func(dst string, dstType types.Type),
+) {
+	if setter := g.fieldSetter(owner, field); setter != nil {
+		g.convertIntoSetter(r, setter, convert)
+		return
+	}
+
+	convert(g.fieldDestination(owner, field), field.Type())
+}
+
+// convertIntoSetter запись значения в результат сеттером. Значение отрисовывается функцией convert во временную
+// переменную, ошибка сеттера, если он её возвращает, прерывает конвертацию.
+func (g *Generator) convertIntoSetter(
+	r *gogh.GoRenderer[*imports.Imports],
+	setter *types.Func,
+	convert func(dst string, dstType types.Type),
+) {
+	sig := setter.Type().(*types.Signature)
+	param := sig.Params().At(0).Type()
+	arg := gogh.Private(gogh.Underscored(strings.TrimPrefix(setter.Name(), "Set")), "arg")
+
+	r.L(`{`)
+	r.L(`var $0 $1`, arg, r.Type(param))
+	convert(arg, param)
+	switch {
+	case sig.Results().Len() == 1 && sig.Results().At(0).Type().String() == "error":
+		r.L(`if err := res.$0($1); err != nil {`, setter.Name(), arg)
+		g.renderWrappedReturn(r, "call setter "+setter.Name())
+		r.L(`}`)
+	case sig.Results().Len() == 1 && !isPointer(sig.Results().At(0).Type()):
+		// неизменяемый построитель возвращает новое значение вместо изменения исходного
+		r.L(`res = res.$0($1)`, setter.Name(), arg)
+	default:
+		r.L(`res.$0($1)`, setter.Name(), arg)
+	}
+	r.L(`}`)
+}
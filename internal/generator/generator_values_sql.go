@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertSQL конвертация значения реализующего driver.Valuer и sql.Scanner в примитив и обратно
+func (g *Generator) convertSQL(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchSQL,
+	whoami string,
+	nilGuarded bool,
+) {
+	if !nilGuarded {
+		r.L(`{`)
+	}
+
+	if !descr.Secondary {
+		// значение получаем методом Value, NULL оставляет приёмник нетронутым
+		r.L(`sqlval, err := $0.Value()`, src)
+		r.L(`if err != nil {`)
+		g.renderConversionError(r, whoami, src)
+		r.L(`}`)
+		r.L(`if sqlval != nil {`)
+		r.L(`primval, ok := sqlval.($0)`, r.Type(unpointer(dstType)))
+		r.L(`if !ok {`)
+		if g.customErrs {
+			r.Imports().Errors().Ref("errors")
+			g.renderErrorReturn(r, `$errors.Newf("convert $0: unexpected driver value type %T", sqlval)`, whoami)
+		} else {
+			r.Imports().Fmt().Ref("fmt")
+			g.renderErrorReturn(r, `$fmt.Errorf("convert $0: unexpected driver value type %T", sqlval)`, whoami)
+		}
+		r.L(`}`)
+		r.N()
+		assign(r, dst, dstType, "primval", unpointer(dstType))
+		r.L(`}`)
+	} else {
+		r.L(`var scanned $0`, r.Type(unpointer(dstType)))
+		r.L(`if err := scanned.Scan($0); err != nil {`, deref(src, srcType))
+		g.renderConversionError(r, whoami, src)
+		r.L(`}`)
+		r.N()
+		assign(r, dst, dstType, "scanned", unpointer(dstType))
+	}
+
+	if !nilGuarded {
+		r.L(`}`)
+	}
+}
@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertStrconv конвертация строки в число или логическое значение и обратно функциями strconv
+func (g *Generator) convertStrconv(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchStrconv,
+	whoami string,
+	nilGuarded bool,
+) {
+	r.Imports().Add("strconv").Ref("strconv")
+
+	if descr.Secondary {
+		// строка является приёмником, форматирование ошибок не даёт
+		basic := strconvKind(unpointer(srcType))
+		value := deref(src, srcType)
+
+		var call string
+		switch {
+		case basic.Info()&types.IsUnsigned != 0:
+			call = r.S("$strconv.FormatUint($0, 10)", castIfNeeded(r, value, unpointer(srcType), types.Typ[types.Uint64]))
+		case basic.Info()&types.IsInteger != 0:
+			call = r.S("$strconv.FormatInt($0, 10)", castIfNeeded(r, value, unpointer(srcType), types.Typ[types.Int64]))
+		case basic.Info()&types.IsFloat != 0:
+			call = r.S(
+				"$strconv.FormatFloat($0, 'g', -1, $1)",
+				castIfNeeded(r, value, unpointer(srcType), types.Typ[types.Float64]),
+				strconvBitSize(basic),
+			)
+		default:
+			call = r.S("$strconv.FormatBool($0)", castIfNeeded(r, value, unpointer(srcType), types.Typ[types.Bool]))
+		}
+
+		assignSafe(
+			r,
+			dst,
+			dstType,
+			castIfNeeded(r, call, types.Typ[types.String], unpointer(dstType)),
+			unpointer(dstType),
+			nilGuarded,
+		)
+		return
+	}
+
+	// строка является источником, разбираем её с проверкой ошибки
+	basic := strconvKind(unpointer(dstType))
+	value := castIfNeeded(r, deref(src, srcType), unpointer(srcType), types.Typ[types.String])
+
+	var call string
+	var parsed types.Type
+	switch {
+	case basic.Info()&types.IsUnsigned != 0:
+		call = r.S("$strconv.ParseUint($0, 10, $1)", value, strconvBitSize(basic))
+		parsed = types.Typ[types.Uint64]
+	case basic.Info()&types.IsInteger != 0:
+		call = r.S("$strconv.ParseInt($0, 10, $1)", value, strconvBitSize(basic))
+		parsed = types.Typ[types.Int64]
+	case basic.Info()&types.IsFloat != 0:
+		call = r.S("$strconv.ParseFloat($0, $1)", value, strconvBitSize(basic))
+		parsed = types.Typ[types.Float64]
+	default:
+		call = r.S("$strconv.ParseBool($0)", value)
+		parsed = types.Typ[types.Bool]
+	}
+
+	r.L(`if parsed, err := $0; err == nil {`, call)
+	assignSafe(r, dst, dstType, castIfNeeded(r, "parsed", parsed, unpointer(dstType)), unpointer(dstType), true)
+	r.L(`} else {`)
+	g.renderConversionError(r, whoami, src)
+	r.L(`}`)
+}
+
+// strconvBitSize размерность числа для функций strconv, 0 означает размерность int и uint
+func strconvBitSize(t *types.Basic) int {
+	switch t.Kind() {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	case types.Int64, types.Uint64, types.Float64:
+		return 64
+	default:
+		return 0
+	}
+}
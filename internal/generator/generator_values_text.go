@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertText конвертация значения типа с текстовым представлением в строку или []byte и обратно
+func (g *Generator) convertText(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchText,
+	whoami string,
+	nilGuarded bool,
+) {
+	if !descr.Secondary {
+		// тип с текстовым представлением является источником
+		if descr.Stringer {
+			assignSafe(
+				r,
+				dst,
+				dstType,
+				castIfNeeded(r, src+".String()", types.Typ[types.String], unpointer(dstType)),
+				unpointer(dstType),
+				nilGuarded,
+			)
+			return
+		}
+
+		r.L(`if text, err := $0.MarshalText(); err == nil {`, src)
+		bytes := types.NewSlice(types.Typ[types.Byte])
+		assignSafe(r, dst, dstType, castIfNeeded(r, "text", bytes, unpointer(dstType)), unpointer(dstType), true)
+		r.L(`} else {`)
+		g.renderConversionError(r, whoami, src)
+		r.L(`}`)
+		return
+	}
+
+	// тип с текстовым представлением является приёмником, значение разбирается с проверкой ошибки
+	if !nilGuarded {
+		r.L(`{`)
+	}
+
+	text := deref(src, srcType)
+	if _, ok := unpointer(srcType).Underlying().(*types.Basic); ok {
+		text = "[]byte(" + text + ")"
+	}
+
+	r.L(`var text $0`, r.Type(unpointer(dstType)))
+	r.L(`if err := text.UnmarshalText($0); err != nil {`, text)
+	g.renderConversionError(r, whoami, src)
+	r.L(`}`)
+	r.N()
+	assign(r, dst, dstType, "text", unpointer(dstType))
+
+	if !nilGuarded {
+		r.L(`}`)
+	}
+}
+
+// renderConversionError генерация возврата ошибки конвертации значения src
+func (g *Generator) renderConversionError(r *gogh.GoRenderer[*imports.Imports], whoami string, src string) {
+	if g.customErrs {
+		r.Imports().Errors().Ref("errors")
+		g.renderErrorReturn(r, `$errors.Wrap(err, "convert $0").Any("invalid-$1", $2)`, whoami, humanGuess(src), src)
+	} else {
+		r.Imports().Fmt().Ref("fmt")
+		g.renderErrorReturn(r, `$fmt.Errorf("convert $0: %w", err)`, whoami)
+	}
+}
+
+// castIfNeeded приведение значения к типу приёмника, если оно требуется
+func castIfNeeded(r *gogh.GoRenderer[*imports.Imports], value string, valueType types.Type, dstType types.Type) string {
+	if types.Identical(valueType, dstType) {
+		return value
+	}
+
+	return r.S("$0($1)", r.Type(dstType), value)
+}
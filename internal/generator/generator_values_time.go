@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertTime конвертация time.Time и time.Duration в целые числа или строки и обратно согласно политике
+// представления времени
+func (g *Generator) convertTime(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchTime,
+	whoami string,
+	nilGuarded bool,
+) {
+	r.Imports().Add("time").Ref("time")
+
+	if !descr.Secondary {
+		// время является источником, перевод в примитив ошибок не даёт
+		value := src
+		var call string
+		var callType types.Type = types.Typ[types.Int64]
+		switch {
+		case descr.Duration && descr.Policy == timePolicyUnix:
+			call = r.S("int64($0 / $time.Second)", deref(src, srcType))
+		case descr.Duration && descr.Policy == timePolicyUnixMilli:
+			call = r.S("$0.Milliseconds()", value)
+		case descr.Duration && descr.Policy == timePolicyUnixNano:
+			call = r.S("$0.Nanoseconds()", value)
+		case descr.Duration:
+			call = r.S("$0.String()", value)
+			callType = types.Typ[types.String]
+		case descr.Policy == timePolicyUnix:
+			call = r.S("$0.Unix()", value)
+		case descr.Policy == timePolicyUnixMilli:
+			call = r.S("$0.UnixMilli()", value)
+		case descr.Policy == timePolicyUnixNano:
+			call = r.S("$0.UnixNano()", value)
+		default:
+			call = r.S("$0.Format($1)", value, timeLayout(r, descr.Policy))
+			callType = types.Typ[types.String]
+		}
+
+		assignSafe(
+			r,
+			dst,
+			dstType,
+			castIfNeeded(r, call, callType, unpointer(dstType)),
+			unpointer(dstType),
+			nilGuarded,
+		)
+		return
+	}
+
+	// время является приёмником
+	value := deref(src, srcType)
+	if isUnixTimePolicy(descr.Policy) {
+		value = castIfNeeded(r, value, unpointer(srcType), types.Typ[types.Int64])
+
+		var call string
+		switch {
+		case descr.Duration && descr.Policy == timePolicyUnix:
+			call = r.S("$time.Duration($0) * $time.Second", value)
+		case descr.Duration && descr.Policy == timePolicyUnixMilli:
+			call = r.S("$time.Duration($0) * $time.Millisecond", value)
+		case descr.Duration:
+			call = r.S("$time.Duration($0)", value)
+		case descr.Policy == timePolicyUnix:
+			call = r.S("$time.Unix($0, 0)", value)
+		case descr.Policy == timePolicyUnixMilli:
+			call = r.S("$time.UnixMilli($0)", value)
+		default:
+			call = r.S("$time.Unix(0, $0)", value)
+		}
+
+		assignSafe(r, dst, dstType, call, unpointer(dstType), nilGuarded)
+		return
+	}
+
+	// пустая строка означает нулевое значение, остальные разбираются с проверкой ошибки
+	value = castIfNeeded(r, value, unpointer(srcType), types.Typ[types.String])
+	var call string
+	if descr.Duration {
+		call = r.S("$time.ParseDuration($0)", value)
+	} else {
+		call = r.S("$time.Parse($0, $1)", timeLayout(r, descr.Policy), value)
+	}
+
+	r.L(`if $0 != "" {`, value)
+	r.L(`if parsed, err := $0; err == nil {`, call)
+	assign(r, dst, dstType, "parsed", unpointer(dstType))
+	r.L(`} else {`)
+	g.renderConversionError(r, whoami, src)
+	r.L(`}`)
+	r.L(`}`)
+}
+
+// timeLayout формат времени: константа пакета time если политика является её названием, иначе строковый литерал
+func timeLayout(r *gogh.GoRenderer[*imports.Imports], policy string) string {
+	if name, ok := timeLayoutConsts[strings.ToLower(policy)]; ok {
+		return r.S("$time.$0", name)
+	}
+
+	return strconv.Quote(policy)
+}
@@ -0,0 +1,118 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// convertUnion конвертация Thrift-объединения в oneof/закрытый интерфейс и обратно
+func (g *Generator) convertUnion(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	src string,
+	srcType types.Type,
+	descr *FieldMatchUnion,
+	whoami string,
+) {
+	if !descr.Secondary {
+		// объединение является источником, ветвь определяем методами IsSet<Field>
+		union := unpointer(srcType).(*types.Named)
+		r.L(`if n := $0.CountSetFields$1(); n > 1 {`, src, union.Obj().Name())
+		if g.customErrs {
+			r.Imports().Errors().Ref("errors")
+			g.renderErrorReturn(r, `$errors.Newf("$0: %d fields of union are set", n)`, whoami)
+		} else {
+			r.Imports().Fmt().Ref("fmt")
+			g.renderErrorReturn(r, `$fmt.Errorf("$0: %d fields of union are set", n)`, whoami)
+		}
+		r.L(`}`)
+
+		r.L(`switch {`)
+		for _, b := range descr.Branches {
+			r.L(`case $0.$1():`, src, thriftIsSet(union, b.Field))
+			r.L(`var branch $0`, r.Type(unpointer(b.Impl)))
+			if b.Value != nil {
+				g.convertValue(
+					r,
+					"branch."+b.Value.Name(),
+					b.Value.Type(),
+					src+"."+b.Field.Name(),
+					b.Field.Type(),
+					b.Elem,
+					"branch "+b.Field.Name()+" of "+whoami,
+					true,
+				)
+			} else {
+				g.convertValue(
+					r,
+					"branch",
+					unpointer(b.Impl),
+					src+"."+b.Field.Name(),
+					b.Field.Type(),
+					b.Elem,
+					"branch "+b.Field.Name()+" of "+whoami,
+					true,
+				)
+			}
+
+			if isPointer(b.Impl) {
+				r.L(`$0 = &branch`, dst)
+			} else {
+				r.L(`$0 = branch`, dst)
+			}
+		}
+		r.L(`}`)
+
+		return
+	}
+
+	// объединение является приёмником, ветвь определяем по типу реализации
+	union := unpointer(dstType)
+	r.L(`switch v := $0.(type) {`, src)
+	for _, b := range descr.Branches {
+		r.L(`case $0:`, r.Type(b.Impl))
+		r.L(`var union $0`, r.Type(union))
+
+		// значение ветви собираем в отдельной переменной: выбранная ветвь должна оказаться заполненной даже
+		// при нулевом значении
+		r.L(`var value $0`, r.Type(unpointer(b.Field.Type())))
+		if b.Value != nil {
+			g.convertValue(
+				r,
+				"value",
+				unpointer(b.Field.Type()),
+				"v."+b.Value.Name(),
+				b.Value.Type(),
+				b.Elem,
+				"branch "+b.Field.Name()+" of "+whoami,
+				false,
+			)
+		} else {
+			g.convertValue(
+				r,
+				"value",
+				unpointer(b.Field.Type()),
+				"v",
+				b.Impl,
+				b.Elem,
+				"branch "+b.Field.Name()+" of "+whoami,
+				true,
+			)
+		}
+		if isPointer(b.Field.Type()) {
+			r.L(`union.$0 = &value`, b.Field.Name())
+		} else {
+			r.L(`union.$0 = value`, b.Field.Name())
+		}
+
+		if isPointer(dstType) {
+			r.L(`$0 = &union`, dst)
+		} else {
+			r.L(`$0 = union`, dst)
+		}
+	}
+	r.L(`}`)
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// declareValueObjectArgs объявление аргументов конструктора типа-значения, в которые пишутся значения
+// сопоставленных полей. Несопоставленные аргументы остаются нулевыми.
+func (g *Generator) declareValueObjectArgs(r *gogh.GoRenderer[*imports.Imports], vo *valueObject) {
+	for i := 0; i < vo.fields.NumFields(); i++ {
+		f := vo.fields.Field(i)
+		r.L(`    var arg$0 $1`, f.Name(), r.Type(f.Type()))
+	}
+}
+
+// constructValueObject построение типа-значения его конструктором из ранее заполненных аргументов. Возвращает
+// выражение указателя на результат.
+func (g *Generator) constructValueObject(r *gogh.GoRenderer[*imports.Imports], vo *valueObject, name string) string {
+	var args []string
+	for i := 0; i < vo.fields.NumFields(); i++ {
+		args = append(args, "arg"+vo.fields.Field(i).Name())
+	}
+
+	sig := vo.constructor.Type().(*types.Signature)
+	call := r.S("$0($1)", g.callName(r, vo.constructor), strings.Join(args, ", "))
+
+	r.N()
+	r.L(`// build $0 with its constructor`, name)
+	if sig.Results().Len() == 2 {
+		r.L(`res, err := $0`, call)
+		r.L(`if err != nil {`)
+		g.renderWrappedReturn(r, "construct "+name)
+		r.L(`}`)
+	} else {
+		r.L(`res := $0`, call)
+	}
+
+	if isPointer(sig.Results().At(0).Type()) {
+		return "res"
+	}
+
+	return "&res"
+}
+
+// convertValueObjectField конвертация значения поля типа-значения, которое читается методом доступа
+func (g *Generator) convertValueObjectField(
+	r *gogh.GoRenderer[*imports.Imports],
+	dst string,
+	dstType types.Type,
+	vo *valueObject,
+	field *types.Var,
+	descr FieldMatchDescription,
+	whoami string,
+) {
+	g.convertMethodValue(r, dst, dstType, vo.accessors[field], descr, whoami)
+}
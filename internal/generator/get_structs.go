@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"go/ast"
 	"go/token"
 	"go/types"
 
@@ -18,32 +19,59 @@ func (d structDescription) String() string {
 	return d.pkg + ":" + d.name
 }
 
-// getOrigStructs получение конвертируемых типов
-func (g *Generator) getOrigStructs(descrs ...structDescription) (map[string]*types.Named, error) {
-	if g.fs == nil {
-		g.fs = token.NewFileSet()
-	}
-
-	var packageNames []string
-	for _, pkg := range descrs {
-		packageNames = append(packageNames, pkg.pkg)
-	}
+// Packages загруженные пакеты. Могут использоваться несколькими генераторами, чтобы не загружать одни и те же
+// пакеты для каждого из них.
+type Packages struct {
+	fs   *token.FileSet
+	pkgs []*packages.Package
+}
 
+// LoadPackages загрузка пакетов с данными путями за один раз
+func LoadPackages(paths ...string) (*Packages, error) {
+	fs := token.NewFileSet()
 	pkgs, err := packages.Load(
 		&packages.Config{
 			Mode: packages.NeedImports | packages.NeedTypes | packages.NeedName | packages.NeedDeps |
 				packages.NeedSyntax | packages.NeedFiles | packages.NeedModule,
-			Fset:  g.fs,
+			Fset:  fs,
 			Tests: false,
 		},
-		packageNames...,
+		paths...,
 	)
 	if err != nil {
 		return nil, errors.Wrap(err, "parse package")
 	}
 
+	return &Packages{
+		fs:   fs,
+		pkgs: pkgs,
+	}, nil
+}
+
+// getOrigStructs получение конвертируемых типов. Пакеты загружаются, если они не были переданы генератору.
+func (g *Generator) getOrigStructs(descrs ...structDescription) (map[string]*types.Named, error) {
+	if g.loaded == nil {
+		var packageNames []string
+		for _, pkg := range descrs {
+			packageNames = append(packageNames, pkg.pkg)
+		}
+
+		loaded, err := LoadPackages(packageNames...)
+		if err != nil {
+			return nil, err
+		}
+		g.loaded = loaded
+	}
+	g.fs = g.loaded.fs
+
+	if g.syntax == nil {
+		g.syntax = map[string][]*ast.File{}
+	}
+
 	res := map[string]*types.Named{}
-	for _, p := range pkgs {
+	for _, p := range g.loaded.pkgs {
+		g.syntax[p.PkgPath] = p.Syntax
+
 		for _, descr := range descrs {
 			if p.PkgPath != descr.pkg {
 				continue
@@ -59,8 +87,18 @@ func (g *Generator) getOrigStructs(descrs ...structDescription) (map[string]*typ
 			switch vv := v.Underlying().(type) {
 			case *types.Struct:
 				res[descr.String()] = v
+			case *types.Map:
+				if !isStringKeyedMap(vv) {
+					return nil, errors.Newf("can only process maps with string keys, but %s is %s", descr.name, vv)
+				}
+				res[descr.String()] = v
 			default:
-				return nil, errors.Newf("can only process %T, but %s is %T", &types.Struct{}, descr.name, vv)
+				return nil, errors.Newf(
+					"can only process %T and maps with string keys, but %s is %T",
+					&types.Struct{},
+					descr.name,
+					vv,
+				)
 			}
 		}
 	}
@@ -73,3 +111,58 @@ func (g *Generator) getOrigStructs(descrs ...structDescription) (map[string]*typ
 
 	return res, nil
 }
+
+// getOutPackage получение пакета сгенерированного кода, nil если его ещё нет. Пакет загружается отдельно, если
+// его нет среди уже загруженных.
+func (g *Generator) getOutPackage() (*packages.Package, error) {
+	pkgs := g.loaded
+	if pkgs.lookup(g.outPath) == nil {
+		loaded, err := LoadPackages(g.outPath)
+		if err != nil {
+			return nil, err
+		}
+		pkgs = loaded
+	}
+
+	p := pkgs.lookup(g.outPath)
+	if p == nil || p.Types == nil || len(p.GoFiles) == 0 {
+		return nil, nil
+	}
+
+	return p, nil
+}
+
+// lookup поиск пакета с данным путём среди загруженных
+func (p *Packages) lookup(path string) *packages.Package {
+	for _, pkg := range p.pkgs {
+		if pkg.PkgPath == path {
+			return pkg
+		}
+	}
+
+	return nil
+}
+
+// getMapLiteral вычисление выражения типа словаря вида map[string]T в контексте пакета pkg
+func (g *Generator) getMapLiteral(pkg *types.Package, expr string) (*types.Map, error) {
+	tv, err := types.Eval(g.fs, pkg, token.NoPos, expr)
+	if err != nil {
+		return nil, errors.Wrap(err, "evaluate type expression")
+	}
+
+	if !tv.IsType() {
+		return nil, errors.Newf("%s is not a type", expr)
+	}
+
+	m, ok := tv.Type.(*types.Map)
+	if !ok || !isStringKeyedMap(m) {
+		return nil, errors.Newf("map with string keys required, got %s", expr)
+	}
+
+	return m, nil
+}
+
+func isStringKeyedMap(m *types.Map) bool {
+	k, ok := m.Key().(*types.Basic)
+	return ok && k.Kind() == types.String
+}
@@ -37,14 +37,24 @@ type fieldBranchDescr struct {
 
 // getFieldsMatches поиск эквивалентных полей.
 // Критерий эквивалентности полей, должны выполняться оба условия:
-//     • Совпадают значения полученные из имён полей с помощью gogh.Underscored либо вручную задано сопоставление
-//        одного поля другому в словаре manual (сейчас не заполняется)
+//     • Поля сопоставлены по имени, в порядке уменьшения приоритета:
+//         • Совпадают значения тегов с ключом tagKey, если он задан (см. lookForSecondaryField)
+//         • Совпадают значения полученные из имён полей с помощью gogh.Underscored (в режиме protoNames для полей
+//           protobuf-структур имена берутся из тега protobuf, см. fieldNames) либо вручную задано сопоставление
+//           одного поля другому в словаре manual (см. WithFieldMapping). Такое сопоставление важнее тегов, а
+//           поле secondary, заданное в нём, не сопоставляется другим полям primary
 //     • Сопоставленные по имени поля имеют эквивалентные типы.
 // Критерий эквивалентности типа:
 //   Типы полей U и V являются эквивалентными (U ~ V) если выполняется одно из следующих условий (в порядке уменьшения
 //   приоритета):
 //     • X ~ X
 //     • X ~ *X
+//     • X является time.Time или time.Duration, а Y целым числом или строкой, если для поля задано представление
+//       времени (unix, unix-ms, unix-ns либо формат)
+//     • X является well-known типом protobuf (golang/protobuf или gogo/protobuf), а Y соответствующим ему
+//       стандартным типом Go: Timestamp ~ time.Time, Duration ~ time.Duration, обёртки XValue ~ Z если Z ~ X.Value
+//     • X является объединением сгенерированным Thrift, а Y oneof-интерфейсом protobuf либо закрытым интерфейсом,
+//       при этом поля X взаимно однозначно сопоставляются с реализациями Y (см. matchUnionBranches)
 //     • В пакете с типом U или V доступны ПУБЛИЧНЫЕ функции и/или методы преобразования из типа X в тип Y и обратно,
 //       где X ~ U и Y ~ V. Данные функции методы не должны принимать никаких аргументов и возвращать либо результат
 //       типа (*)V, или ((*)V, error).
@@ -56,7 +66,10 @@ type fieldBranchDescr struct {
 //                совпадать суффиксы.
 //         • Warning: если типы оба являются перечислениями но не выполняются критерии из этого подпункта, то
 //                    они НЕ являются эквивалентными.
+//     • X является структурой-обёрткой с единственным публичным полем типа Z (см. getNewtypeValue) и Z ~ Y
+//     • X является типом-значением с единственным параметром конструктора типа Z (см. getWrapper) и Z ~ Y
 //     • X и Y приводятся друг к другу и X ~ U, Y ~ V
+//     • X является строкой, а Y числом или логическим значением, если для поля разрешены конвертации через strconv
 //     • []X ~ []Y если X ~ Y
 //     • map[A]B ~ map[X]Y если A ~ X и B ~ Y
 //   Warning: целочисленные типы различных размерностей, например int8 и uin64, считаются эквивалентными в рамках
@@ -72,18 +85,15 @@ type fieldBranchDescr struct {
 //   5. Если тип только найденного поля эквивалентен типу поля в ветви, то считается что найдено соответствие между
 //      ветвью и полем в primary-типе
 //   6. Если для всех ветвей было найдено соответствие в полях, то такие поля удаляются из поматченных
-//
-// TODO реализовать наполнение словаря manual для ручного указания эквивалентных полей
 func (g *Generator) getFieldsMatches(manual map[string]string) ([]fieldMatchInfo, []fieldSecondaryOneof) {
-	prim := g.prim.Underlying().(*types.Struct)
-	sec := g.sec.Underlying().(*types.Struct)
+	prim := g.structOf(g.prim)
+	sec := g.structOf(g.sec)
 
 	var errorsHappened bool
 	var res []fieldMatchInfo
-outer:
 	for i := 0; i < prim.NumFields(); i++ {
 		pf := prim.Field(i)
-		if pf.Name() == "" || !pf.Exported() {
+		if pf.Name() == "" || !g.isAccessible(pf) || g.isSkippedField(prim, pf) {
 			continue
 		}
 
@@ -92,29 +102,28 @@ outer:
 			errorsHappened = true
 		}
 
-		want := gogh.Underscored(pf.Name())
-		if name, ok := manual[want]; ok {
-			want = name
-		}
-
-		for j := 0; j < sec.NumFields(); j++ {
-			ps := sec.Field(j)
-			if gogh.Underscored(ps.Name()) != want {
-				continue
+		var ps *types.Var
+		if name, ok := manual[gogh.Underscored(pf.Name())]; ok {
+			ps = g.lookForMappedField(sec, name)
+		} else {
+			ps = g.lookForSecondaryField(prim, pf, sec, g.fieldNames(prim, pf))
+			if ps != nil && isMappedField(manual, ps) {
+				ps = nil
 			}
-
-			eq := g.getTypeMatchDescription(pf.Type(), ps.Type())
+		}
+		if ps == nil {
 			res = append(res, fieldMatchInfo{
 				prim:  pf,
-				sec:   ps,
-				descr: eq,
+				descr: &FieldMatchNoMatch{},
 			})
-			continue outer
+			continue
 		}
 
+		eq := g.getFieldTypeMatchDescription(pf, pf.Type(), ps.Type())
 		res = append(res, fieldMatchInfo{
 			prim:  pf,
-			descr: &FieldMatchNoMatch{},
+			sec:   ps,
+			descr: eq,
 		})
 	}
 
@@ -122,12 +131,16 @@ outer:
 		message.Fatal("unhandled types met, cannot continue")
 	}
 
-	res, oneofs := g.matchOneofs(sec, res)
+	res, oneofs := g.matchOneofs(prim, sec, res)
 
 	return res, oneofs
 }
 
-func (g *Generator) matchOneofs(sec *types.Struct, res []fieldMatchInfo) ([]fieldMatchInfo, []fieldSecondaryOneof) {
+func (g *Generator) matchOneofs(
+	prim *types.Struct,
+	sec *types.Struct,
+	res []fieldMatchInfo,
+) ([]fieldMatchInfo, []fieldSecondaryOneof) {
 	// ищем oneof-поля
 	var oneofs []fieldSecondaryOneof
 oouter:
@@ -146,14 +159,20 @@ oouter:
 		}
 
 		tt, ok := t.Underlying().(*types.Interface)
-
-		// и этот тип должен иметь единственный метод имеющий такое же название
-		if tt.NumMethods() != 1 {
+		if !ok {
 			continue
 		}
 
-		m := tt.Method(0)
-		if m.Name() != magicOneofName {
+		// и этот тип должен иметь метод с таким же названием. В случае gogo/protobuf с включенными плагинами
+		// маршалинга у интерфейса имеются и другие методы (MarshalTo, Size, Equal и т.п.), они не мешают.
+		var m *types.Func
+		for j := 0; j < tt.NumMethods(); j++ {
+			if tt.Method(j).Name() == magicOneofName {
+				m = tt.Method(j)
+				break
+			}
+		}
+		if m == nil {
 			continue
 		}
 
@@ -182,46 +201,51 @@ oouter:
 
 			f := s.Field(0)
 
+			// ищем геттер ветви на secondary-структуре. gogo/protobuf с goproto_getters=false геттеров не
+			// генерирует, в этом случае опираемся на тип поля в обёртке ветви.
+			var getter *types.Func
+			resType := f.Type()
 			for j := 0; j < g.sec.NumMethods(); j++ {
 				mt := g.sec.Method(j)
 				if mt.Name() != gogh.Proto("get", f.Name()) {
 					continue
 				}
 
-				resType := mt.Type().(*types.Signature).Results().At(0).Type()
-				match := g.getTypeMatchDescription(resType, f.Type())
-				if _, ok := match.(*FieldMatchNoMatch); ok {
-					continue oouter
+				getter = mt
+				resType = mt.Type().(*types.Signature).Results().At(0).Type()
+				break
+			}
+
+			match := g.getTypeMatchDescription(fieldPolicy{}, resType, f.Type())
+			if _, ok := match.(*FieldMatchNoMatch); ok {
+				continue oouter
+			}
+
+			// проверяем тип ветви и что соотв. поле не входит в число заматченных на предыдущем этапе
+			for i, m := range res {
+				if _, ok := m.descr.(*FieldMatchNoMatch); !ok {
+					continue
 				}
 
-				// геттер для ветви найден, проверяем его тип и что соотв. поле не входит в число заматченных
-				// на предыдущем этапе
-				for i, m := range res {
-					if _, ok := m.descr.(*FieldMatchNoMatch); !ok {
-						continue
-					}
-
-					if gogh.Underscored(m.prim.Name()) != gogh.Underscored(f.Name()) {
-						continue
-					}
-
-					match := g.getTypeMatchDescription(m.prim.Type(), resType)
-					if _, ok := match.(*FieldMatchNoMatch); ok {
-						continue
-					}
-
-					// поле с именем бранча имеет эквивалентный тип и не сопоставлено никакому другому, добавляем
-					// ветвь
-					oneof = append(oneof, fieldBranchDescr{
-						branch: m.prim.Name(),
-						getter: mt,
-						prim:   m.prim,
-						sec:    s.Field(0),
-						descr:  match,
-					})
-					exclude[i] = struct{}{}
+				if !g.fieldsMatch(prim, m.prim, s, f) {
+					continue
 				}
 
+				match := g.getFieldTypeMatchDescription(m.prim, m.prim.Type(), resType)
+				if _, ok := match.(*FieldMatchNoMatch); ok {
+					continue
+				}
+
+				// поле с именем бранча имеет эквивалентный тип и не сопоставлено никакому другому, добавляем
+				// ветвь
+				oneof = append(oneof, fieldBranchDescr{
+					branch: m.prim.Name(),
+					getter: getter,
+					prim:   m.prim,
+					sec:    s.Field(0),
+					descr:  match,
+				})
+				exclude[i] = struct{}{}
 			}
 		}
 
@@ -246,7 +270,33 @@ oouter:
 	return res, oneofs
 }
 
-func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDescription {
+// fieldPolicy настройки сопоставления типов, заданные для отдельного primary-поля
+type fieldPolicy struct {
+	// strconv конвертации через strconv разрешены
+	strconv bool
+	// time представление времени, время не сопоставляется с числами и строками если оно не задано
+	time string
+	// sql значение типа реализующего driver.Valuer сопоставляется с любым примитивом driver.Value
+	sql bool
+}
+
+// getFieldTypeMatchDescription сопоставление типов с учётом настроек данного primary-поля: конвертации
+// через strconv и представление времени могут задаваться для отдельных полей.
+func (g *Generator) getFieldTypeMatchDescription(pf *types.Var, prim, sec types.Type) FieldMatchDescription {
+	_, ok := g.strconvFields[pf.Name()]
+	policy := fieldPolicy{
+		strconv: g.strconvAll || ok,
+		time:    g.timeAll,
+	}
+	if v, ok := g.timeFields[pf.Name()]; ok {
+		policy.time = v
+	}
+	_, policy.sql = g.sqlFields[pf.Name()]
+
+	return g.getTypeMatchDescription(policy, prim, sec)
+}
+
+func (g *Generator) getTypeMatchDescription(policy fieldPolicy, prim, sec types.Type) FieldMatchDescription {
 	// если один и тот же тип
 	if prim == sec || types.AssignableTo(prim, sec) {
 		return &FieldMatchDirect{}
@@ -256,12 +306,33 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 
 	// вначале на prim
 	if v, ok := prim.(*types.Pointer); ok {
-		return g.getTypeMatchDescription(v.Elem(), sec)
+		return g.getTypeMatchDescription(policy, v.Elem(), sec)
 	}
 
 	// потом на sec
 	if v, ok := sec.(*types.Pointer); ok {
-		return g.getTypeMatchDescription(prim, v.Elem())
+		return g.getTypeMatchDescription(policy, prim, v.Elem())
+	}
+
+	// время и продолжительности сопоставляются с числами и строками согласно заданному представлению
+	if v, ok := g.matchTime(policy, prim, sec); ok {
+		return v
+	}
+
+	// well-known типы protobuf (в т.ч. gogo) сопоставляются со стандартными типами Go
+	if v, ok := g.matchWellKnown(policy, prim, sec); ok {
+		return v
+	}
+
+	// Thrift-объединения сопоставляются с oneof protobuf и закрытыми интерфейсами
+	if v, ok := g.matchUnion(policy, prim, sec); ok {
+		return v
+	}
+
+	// типы с текстовым представлением сопоставляются строкам и []byte. Это делается до поиска функций
+	// преобразования, которые для таких типов находят посторонние методы вроде netip.Addr.Zone
+	if v, ok := g.matchText(prim, sec); ok {
+		return v
 	}
 
 	// если имеются функции преобразования между типами
@@ -282,6 +353,11 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 		}
 	}
 
+	// структуры-обёртки с единственным публичным полем сопоставляются с типом этого поля
+	if v, ok := g.matchNewtype(policy, prim, sec); ok {
+		return v
+	}
+
 	// если подозрительно похожие енумии
 	penum, senum, enummatch := g.matchEnums(prim, sec)
 	switch enummatch {
@@ -311,8 +387,19 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 		}
 	}
 
+	// значения реализующие driver.Valuer и sql.Scanner сопоставляются примитивам возвращаемым методом Value.
+	// Типы, которые просто приводятся к примитиву, сопоставлены выше, т.к. приведение не может завершиться ошибкой
+	if v, ok := g.matchSQL(policy, prim, sec); ok {
+		return v
+	}
+
+	// строки, слайсы и массивы байт копируются друг в друга
+	if v, ok := g.matchBytes(prim, sec); ok {
+		return v
+	}
+
 	// в случае слайсов типы должны быть эквивалентными
-	sliceMatchDescr, sliceMatch := g.areEquivalentSlices(prim, sec)
+	sliceMatchDescr, sliceMatch := g.areEquivalentSlices(policy, prim, sec)
 	switch sliceMatch {
 	case sliceMatchStateNoSlices:
 		// оба не слайсы, продолжаем дальше
@@ -323,7 +410,7 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 	}
 
 	// в случае словарей типы и ключей, и значений так же должны быть эквивалентыми
-	mapMatchDecr, mapMatch := g.areEquivalentMaps(prim, sec)
+	mapMatchDecr, mapMatch := g.areEquivalentMaps(policy, prim, sec)
 	switch mapMatch {
 	case mapMatchStateNomaps:
 		// оба не мапы, продолжаем дальше
@@ -333,6 +420,11 @@ func (g *Generator) getTypeMatchDescription(prim, sec types.Type) FieldMatchDesc
 		return mapMatchDecr
 	}
 
+	// строки с числами и логическими значениями, если это разрешено для поля
+	if v, ok := g.matchStrconv(policy, prim, sec); ok {
+		return v
+	}
+
 	if basicAssignable(prim, sec) {
 		return &FieldMatchCastable{}
 	}
@@ -425,11 +517,15 @@ func canBeEnum(t types.Type) bool {
 func (g *Generator) reportMatchingInfo(
 	m []fieldMatchInfo,
 	oos []fieldSecondaryOneof,
-) (missingPrimary bool, missingSecondary bool) {
+	methods []fieldMethodMatch,
+) {
 	message.Info("\nregular fields matches")
 
+	var missingPrimary bool
+
 	for _, info := range m {
-		if _, ok := info.descr.(*FieldMatchNoMatch); ok {
+		_, nomatch := info.descr.(*FieldMatchNoMatch)
+		if nomatch && isRequired(info.prim) && !methodCovers(methods, info.prim, false, true) {
 			missingPrimary = true
 		}
 
@@ -442,6 +538,8 @@ func (g *Generator) reportMatchingInfo(
 				info.sec.Type(),
 				info.descr,
 			)
+		} else if !isRequired(info.prim) {
+			message.Infof("primary field %s (%s): skipped", info.prim.Name(), info.prim.Type())
 		} else {
 			message.Warningf("primary field %s (%s): %s", info.prim.Name(), info.prim.Type(), info.descr)
 		}
@@ -464,7 +562,51 @@ func (g *Generator) reportMatchingInfo(
 		}
 	}
 
-	missingSecondary = g.secondaryHasUncoveredFields(m, oos)
+	for i, mm := range methods {
+		if i == 0 {
+			message.Info("\nmethods matches")
+		}
+		switch {
+		case mm.setter && mm.primary:
+			message.Infof(
+				"secondary %s(%s) → primary setter %s(): %s",
+				mm.field.Name(),
+				mm.field.Type(),
+				mm.method.Name(),
+				mm.descr,
+			)
+		case mm.setter:
+			message.Infof(
+				"primary %s(%s) → secondary setter %s(): %s",
+				mm.field.Name(),
+				mm.field.Type(),
+				mm.method.Name(),
+				mm.descr,
+			)
+		case mm.primary:
+			message.Infof(
+				"primary method %s() → secondary %s(%s): %s",
+				mm.method.Name(),
+				mm.field.Name(),
+				mm.field.Type(),
+				mm.descr,
+			)
+		default:
+			message.Infof(
+				"secondary method %s() → primary %s(%s): %s",
+				mm.method.Name(),
+				mm.field.Name(),
+				mm.field.Type(),
+				mm.descr,
+			)
+		}
+	}
+
+	missingSecondary := g.secondaryHasUncoveredFields(m, oos, methods)
+
+	// несопоставленные поля важны только для генерируемых направлений
+	missingPrimary = missingPrimary && g.primToSec()
+	missingSecondary = missingSecondary && g.secToPrim()
 
 	message.Info()
 
@@ -475,39 +617,43 @@ func (g *Generator) reportMatchingInfo(
 	if missingSecondary {
 		message.Warning("not all secondary fields were matched")
 	}
-
-	return missingPrimary, missingSecondary
 }
 
 // secondaryHasUncoveredFields выяснение, что имеются публичные поля в secondary-типе для которых не найдено
 // соответствие в primary.
-func (g *Generator) secondaryHasUncoveredFields(ms []fieldMatchInfo, oos []fieldSecondaryOneof) bool {
-	sec := g.sec.Underlying().(*types.Struct)
+func (g *Generator) secondaryHasUncoveredFields(
+	ms []fieldMatchInfo,
+	oos []fieldSecondaryOneof,
+	methods []fieldMethodMatch,
+) bool {
+	sec := g.structOf(g.sec)
 
-outer:
 	for i := 0; i < sec.NumFields(); i++ {
 		f := sec.Field(i)
-		if !f.Exported() {
+		if !g.isAccessible(f) || !isRequired(f) || g.isSkippedField(sec, f) {
 			continue
 		}
 
-		for _, m := range ms {
-			if m.sec == nil {
-				continue outer
-			}
-
-			if m.sec.Id() == f.Id() {
-				continue outer
-			}
+		if !g.secondaryFieldCovered(f, ms, oos) && !methodCovers(methods, f, true, false) {
+			return true
 		}
+	}
 
-		for _, oo := range oos {
-			if oo.sec.Id() == f.Id() {
-				continue outer
-			}
+	return false
+}
+
+// secondaryFieldCovered выяснение, что полю secondary-типа сопоставлено поле или oneof primary-типа
+func (g *Generator) secondaryFieldCovered(f *types.Var, ms []fieldMatchInfo, oos []fieldSecondaryOneof) bool {
+	for _, m := range ms {
+		if m.sec != nil && m.sec.Id() == f.Id() {
+			return true
 		}
+	}
 
-		return true
+	for _, oo := range oos {
+		if oo.sec.Id() == f.Id() {
+			return true
+		}
 	}
 
 	return false
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/sirkon/gogh"
+	"github.com/sirkon/metamorph/internal/imports"
+)
+
+// isAccessible доступность поля из сгенерированного кода. Непубличные поля используются только если обе
+// структуры находятся в пакете, куда пишется сгенерированный код.
+func (g *Generator) isAccessible(f *types.Var) bool {
+	if f.Exported() {
+		return true
+	}
+
+	return g.localTypes && f.Pkg() != nil && f.Pkg().Path() == g.outPath
+}
+
+// isRequired поле требует конвертации даже при отсутствии соответствия. Непубличные поля без соответствия вроде
+// мьютексов и кэшей в конвертации не участвуют: ни расширений для них, ни предупреждений.
+func isRequired(f *types.Var) bool {
+	return f.Exported()
+}
+
+// primName имя primary-типа в сгенерированном коде, квалифицированное если код пишется в другой пакет
+func (g *Generator) primName(r *gogh.GoRenderer[*imports.Imports]) string {
+	if g.prim.Obj().Pkg().Path() == g.outPath {
+		return g.prim.Obj().Name()
+	}
+
+	r.Imports().Add(g.prim.Obj().Pkg().Path()).Ref("primpkg")
+	return r.S("$primpkg.$0", g.prim.Obj().Name())
+}
+
+// outPackageName имя пакета сгенерированного кода по его пути
+func outPackageName(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	return strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// hasPrivateTypes хотя бы одна из конвертируемых структур непубличная
+func (g *Generator) hasPrivateTypes() bool {
+	return !g.prim.Obj().Exported() || g.sec != nil && !g.sec.Obj().Exported()
+}
+
+// primToSecName имя функции конвертации primary → secondary. Функции конвертации непубличных типов тоже
+// непубличные.
+func (g *Generator) primToSecName(r *gogh.GoRenderer[*imports.Imports], primunder, secunder string) string {
+	if g.hasPrivateTypes() {
+		return r.S("$0To${1|P}", gogh.Private(gogh.Underscored(primunder)), secunder)
+	}
+
+	return r.S("${0|P}To${1|P}", primunder, secunder)
+}
+
+// secToPrimName имя функции конвертации secondary → primary
+func (g *Generator) secToPrimName(r *gogh.GoRenderer[*imports.Imports], secunder, primunder string) string {
+	if g.hasPrivateTypes() {
+		return r.S("$0To${1|P}", gogh.Private(gogh.Underscored(secunder)), primunder)
+	}
+
+	return r.S("${0|P}To${1|P}", secunder, primunder)
+}
+
+// manualName имя функции конвертации расширения, написанной пользователем, для сгенерированной функции name
+func manualName(name string) string {
+	return "manual" + strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,48 @@
+package generator
+
+import "go/types"
+
+// matchBytes сопоставление байтовых типов: строки со слайсом байт и массива байт со слайсом байт.
+// Указатели на данном этапе уже сняты.
+func (g *Generator) matchBytes(prim, sec types.Type) (FieldMatchDescription, bool) {
+	p := byteLikeKind(prim)
+	s := byteLikeKind(sec)
+	if p == byteLikeNone || s == byteLikeNone || p == s {
+		return nil, false
+	}
+
+	// строки и массивы друг с другом не сопоставляются
+	if p != byteLikeSlice && s != byteLikeSlice {
+		return nil, false
+	}
+
+	return &FieldMatchBytes{}, true
+}
+
+type byteLike int
+
+const (
+	byteLikeNone byteLike = iota
+	byteLikeString
+	byteLikeSlice
+	byteLikeArray
+)
+
+func byteLikeKind(t types.Type) byteLike {
+	switch v := t.Underlying().(type) {
+	case *types.Basic:
+		if v.Kind() == types.String {
+			return byteLikeString
+		}
+	case *types.Slice:
+		if isByte(v.Elem()) {
+			return byteLikeSlice
+		}
+	case *types.Array:
+		if isByte(v.Elem()) {
+			return byteLikeArray
+		}
+	}
+
+	return byteLikeNone
+}
@@ -2,6 +2,7 @@ package generator
 
 import (
 	"fmt"
+	"go/types"
 	"strings"
 )
 
@@ -20,6 +21,12 @@ func (m *FieldMatchNoMatch) String() string {
 
 func (*FieldMatchNoMatch) isFieldMatchDescription() {}
 
+// isNoMatch выяснение, что сопоставление не удалось
+func isNoMatch(descr FieldMatchDescription) bool {
+	_, ok := descr.(*FieldMatchNoMatch)
+	return ok
+}
+
 // FieldMatchDirect branch of FieldMatchDescription
 type FieldMatchDirect struct{}
 
@@ -128,6 +135,151 @@ func (m *FieldMatchMap) String() string {
 
 func (*FieldMatchMap) isFieldMatchDescription() {}
 
+// FieldMatchWrapper branch of FieldMatchDescription
+type FieldMatchWrapper struct {
+	// Secondary обёртка находится на стороне secondary-типа
+	Secondary bool
+	// Value поле обёртки хранящее значение
+	Value *types.Var
+	// Accessor метод доступа к значению, nil если значение читается из поля Value
+	Accessor *types.Func
+	// Constructor функция построения обёртки из значения, nil если обёртка строится литералом
+	Constructor *types.Func
+	// Elem описание конвертации между значением обёртки и значением другой стороны
+	Elem FieldMatchDescription
+}
+
+func (w *FieldMatchWrapper) String() string {
+	if w.Secondary {
+		return fmt.Sprintf("secondary is a wrapper where value is %s", w.Elem)
+	}
+
+	return fmt.Sprintf("primary is a wrapper where value is %s", w.Elem)
+}
+
+func (*FieldMatchWrapper) isFieldMatchDescription() {}
+
+// FieldMatchUnion branch of FieldMatchDescription
+type FieldMatchUnion struct {
+	// Secondary Thrift-объединение находится на стороне secondary-типа
+	Secondary bool
+	// Branches сопоставление полей объединения и ветвей другой стороны
+	Branches []*FieldMatchUnionBranch
+}
+
+// FieldMatchUnionBranch ветвь сопоставления Thrift-объединения
+type FieldMatchUnionBranch struct {
+	// Field поле объединения
+	Field *types.Var
+	// Impl реализация интерфейса другой стороны
+	Impl types.Type
+	// Value поле значения ветви в обёртке oneof, nil если значением ветви является сама реализация
+	Value *types.Var
+	// Elem описание конвертации между полем объединения и значением ветви
+	Elem FieldMatchDescription
+}
+
+func (u *FieldMatchUnion) String() string {
+	var branches []string
+	for _, b := range u.Branches {
+		branches = append(branches, fmt.Sprintf("%s ↔ %s (%s)", b.Field.Name(), b.Impl, b.Elem))
+	}
+
+	return fmt.Sprintf("thrift union with branches %s", strings.Join(branches, ", "))
+}
+
+func (*FieldMatchUnion) isFieldMatchDescription() {}
+
+// FieldMatchText branch of FieldMatchDescription
+type FieldMatchText struct {
+	// Secondary тип с текстовым представлением находится на стороне secondary-типа
+	Secondary bool
+	// Stringer текстовое представление получается методом String, а не MarshalText
+	Stringer bool
+}
+
+func (t *FieldMatchText) String() string {
+	side := "primary"
+	if t.Secondary {
+		side = "secondary"
+	}
+
+	if t.Stringer {
+		return fmt.Sprintf("%s is encoded as text with String and decoded with UnmarshalText", side)
+	}
+
+	return fmt.Sprintf("%s is encoded as text with MarshalText and decoded with UnmarshalText", side)
+}
+
+func (*FieldMatchText) isFieldMatchDescription() {}
+
+// FieldMatchSQL branch of FieldMatchDescription
+type FieldMatchSQL struct {
+	// Secondary тип реализующий driver.Valuer и sql.Scanner находится на стороне secondary-типа
+	Secondary bool
+}
+
+func (s *FieldMatchSQL) String() string {
+	if s.Secondary {
+		return "secondary is converted with driver.Valuer and sql.Scanner"
+	}
+
+	return "primary is converted with driver.Valuer and sql.Scanner"
+}
+
+func (*FieldMatchSQL) isFieldMatchDescription() {}
+
+// FieldMatchBytes branch of FieldMatchDescription
+type FieldMatchBytes struct{}
+
+func (b *FieldMatchBytes) String() string {
+	return "byte-like types copied"
+}
+
+func (*FieldMatchBytes) isFieldMatchDescription() {}
+
+// FieldMatchStrconv branch of FieldMatchDescription
+type FieldMatchStrconv struct {
+	// Secondary строка находится на стороне secondary-типа
+	Secondary bool
+}
+
+func (s *FieldMatchStrconv) String() string {
+	if s.Secondary {
+		return "secondary string is parsed and formatted with strconv"
+	}
+
+	return "primary string is parsed and formatted with strconv"
+}
+
+func (*FieldMatchStrconv) isFieldMatchDescription() {}
+
+// FieldMatchTime branch of FieldMatchDescription
+type FieldMatchTime struct {
+	// Secondary time.Time или time.Duration находится на стороне secondary-типа
+	Secondary bool
+	// Duration конвертируется time.Duration, а не time.Time
+	Duration bool
+	// Policy представление времени: unix, unix-ms, unix-ns либо формат
+	Policy string
+}
+
+func (t *FieldMatchTime) String() string {
+	side := "primary"
+	if t.Secondary {
+		side = "secondary"
+	}
+
+	typ := "time"
+	if t.Duration {
+		typ = "duration"
+	}
+
+	return fmt.Sprintf("%s %s is represented as %s", side, typ, t.Policy)
+}
+
+func (*FieldMatchTime) isFieldMatchDescription() {}
+
 var (
 	_ FieldMatchDescription = &FieldMatchNoMatch{}
 	_ FieldMatchDescription = &FieldMatchDirect{}
@@ -136,4 +288,11 @@ var (
 	_ FieldMatchDescription = &FieldMatchCastable{}
 	_ FieldMatchDescription = &FieldMatchSlice{}
 	_ FieldMatchDescription = &FieldMatchMap{}
+	_ FieldMatchDescription = &FieldMatchWrapper{}
+	_ FieldMatchDescription = &FieldMatchUnion{}
+	_ FieldMatchDescription = &FieldMatchText{}
+	_ FieldMatchDescription = &FieldMatchSQL{}
+	_ FieldMatchDescription = &FieldMatchBytes{}
+	_ FieldMatchDescription = &FieldMatchStrconv{}
+	_ FieldMatchDescription = &FieldMatchTime{}
 )
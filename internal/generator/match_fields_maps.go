@@ -15,7 +15,7 @@ const (
 	mapMatchStateMatched
 )
 
-func (g *Generator) areEquivalentMaps(prim, sec types.Type) (*FieldMatchMap, mapMatchState) {
+func (g *Generator) areEquivalentMaps(policy fieldPolicy, prim, sec types.Type) (*FieldMatchMap, mapMatchState) {
 	p, pok := prim.(*types.Map)
 	s, sok := sec.(*types.Map)
 
@@ -29,7 +29,7 @@ func (g *Generator) areEquivalentMaps(prim, sec types.Type) (*FieldMatchMap, map
 
 	var res FieldMatchMap
 
-	kmatch := g.getTypeMatchDescription(p.Key(), s.Key())
+	kmatch := g.getTypeMatchDescription(policy, p.Key(), s.Key())
 	switch v := kmatch.(type) {
 	case *FieldMatchNoMatch:
 		return nil, mapMatchStateDifferentMaps
@@ -37,7 +37,7 @@ func (g *Generator) areEquivalentMaps(prim, sec types.Type) (*FieldMatchMap, map
 		res.Key = v
 	}
 
-	ematch := g.getTypeMatchDescription(p.Elem(), s.Elem())
+	ematch := g.getTypeMatchDescription(policy, p.Elem(), s.Elem())
 	switch v := ematch.(type) {
 	case *FieldMatchNoMatch:
 		return nil, mapMatchStateDifferentMaps
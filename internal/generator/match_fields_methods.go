@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+)
+
+// fieldMethodMatch сопоставление поля одного типа методу другого: либо методу без параметров, значение которого
+// записывается в это поле, либо сеттеру, которому передаётся значение этого поля
+type fieldMethodMatch struct {
+	// primary метод принадлежит primary-типу, иначе secondary-типу
+	primary bool
+	// setter метод является сеттером приёмника
+	setter bool
+	method *types.Func
+	field  *types.Var
+	// descr описание конвертации в ориентации primary ↔ secondary, как и у fieldMatchInfo
+	descr FieldMatchDescription
+}
+
+// intoSecondary сопоставление используется при конвертации primary → secondary
+func (m fieldMethodMatch) intoSecondary() bool {
+	return m.primary != m.setter
+}
+
+// getMethodsMatches поиск методов источника для полей приёмника, которые не были сопоставлены никаким полям.
+// Подходят публичные методы без параметров возвращающие значение либо (значение, error), имя метода сопоставляется
+// имени поля через gogh.Underscored, а тип значения должен быть эквивалентен типу поля.
+func (g *Generator) getMethodsMatches(matches []fieldMatchInfo, oos []fieldSecondaryOneof) []fieldMethodMatch {
+	var res []fieldMethodMatch
+
+	// поля primary-типа заполняемые методами secondary-типа
+	for _, m := range matches {
+		if _, ok := m.descr.(*FieldMatchNoMatch); !ok {
+			continue
+		}
+		if _, ok := g.xclude[m.prim.Name()]; ok {
+			continue
+		}
+
+		method := lookForValueMethod(g.sec, m.prim.Name())
+		if method == nil {
+			continue
+		}
+
+		value := method.Type().(*types.Signature).Results().At(0).Type()
+		descr := g.getFieldTypeMatchDescription(m.prim, m.prim.Type(), value)
+		if _, ok := descr.(*FieldMatchNoMatch); ok {
+			continue
+		}
+
+		res = append(res, fieldMethodMatch{
+			primary: false,
+			method:  method,
+			field:   m.prim,
+			descr:   descr,
+		})
+	}
+
+	// поля secondary-типа заполняемые методами primary-типа
+	sec := g.structOf(g.sec)
+	for i := 0; i < sec.NumFields(); i++ {
+		f := sec.Field(i)
+		if !g.isAccessible(f) || g.isSkippedField(sec, f) || g.secondaryFieldCovered(f, matches, oos) {
+			continue
+		}
+
+		method := lookForValueMethod(g.prim, f.Name())
+		if method == nil {
+			continue
+		}
+
+		// настройки полей задаются по именам primary-полей, для метода используется его имя
+		value := method.Type().(*types.Signature).Results().At(0).Type()
+		pf := types.NewVar(method.Pos(), method.Pkg(), method.Name(), value)
+		descr := g.getFieldTypeMatchDescription(pf, value, f.Type())
+		if _, ok := descr.(*FieldMatchNoMatch); ok {
+			continue
+		}
+
+		res = append(res, fieldMethodMatch{
+			primary: true,
+			method:  method,
+			field:   f,
+			descr:   descr,
+		})
+	}
+
+	return append(res, g.getSettersMatches(matches, oos)...)
+}
+
+// lookForValueMethod поиск публичного метода без параметров с именем сопоставимым имени поля, который возвращает
+// значение либо (значение, error)
+func lookForValueMethod(n *types.Named, field string) *types.Func {
+	mset := types.NewMethodSet(types.NewPointer(n))
+	for i := 0; i < mset.Len(); i++ {
+		m := mset.At(i).Obj().(*types.Func)
+		if !m.Exported() || gogh.Underscored(m.Name()) != gogh.Underscored(field) {
+			continue
+		}
+
+		sig := m.Type().(*types.Signature)
+		if sig.Params().Len() != 0 {
+			continue
+		}
+
+		switch sig.Results().Len() {
+		case 1:
+			return m
+		case 2:
+			if sig.Results().At(1).Type().String() == "error" {
+				return m
+			}
+		}
+	}
+
+	return nil
+}
+
+// methodCovers выяснение, что поле f сопоставлено методу primary-типа (primary == true) либо secondary-типа
+// в направлении конвертации primary → secondary (intoSecondary == true) либо обратном
+func methodCovers(methods []fieldMethodMatch, f *types.Var, primary, intoSecondary bool) bool {
+	for _, m := range methods {
+		if m.primary == primary && m.intoSecondary() == intoSecondary && m.field.Id() == f.Id() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"go/types"
+	"reflect"
+)
+
+// matchNewtype сопоставление структуры-обёртки с единственным публичным полем ("newtype", например
+// type UserID struct{ Value string }) с типом сопоставимым с этим полем. Указатели на данном этапе уже сняты.
+func (g *Generator) matchNewtype(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	// одинаково устроенные структуры приводятся друг к другу, разворачивать их незачем
+	if types.Identical(prim.Underlying(), sec.Underlying()) {
+		return nil, false
+	}
+
+	if n, w := g.getWrapper(prim); w != nil {
+		g.unwrapping[n] = struct{}{}
+		defer delete(g.unwrapping, n)
+
+		elem := g.getTypeMatchDescription(policy, w.Value.Type(), sec)
+		if _, ok := elem.(*FieldMatchNoMatch); !ok {
+			w.Elem = elem
+			return w, true
+		}
+	}
+
+	if n, w := g.getWrapper(sec); w != nil {
+		g.unwrapping[n] = struct{}{}
+		defer delete(g.unwrapping, n)
+
+		elem := g.getTypeMatchDescription(policy, prim, w.Value.Type())
+		if _, ok := elem.(*FieldMatchNoMatch); !ok {
+			w.Secondary = true
+			w.Elem = elem
+			return w, true
+		}
+	}
+
+	return nil, false
+}
+
+// getWrapper описание обёртки без сопоставления её значения, если данный тип является таковой. Это либо
+// структура-обёртка, либо тип-значение с единственным параметром конструктора: значение такого типа читается
+// методом доступа и строится конструктором.
+func (g *Generator) getWrapper(t types.Type) (*types.Named, *FieldMatchWrapper) {
+	if n, value := g.getNewtypeValue(t); value != nil {
+		return n, &FieldMatchWrapper{
+			Value:       value,
+			Constructor: g.getNewtypeConstructor(n, value),
+		}
+	}
+
+	n, ok := t.(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+
+	if _, ok := g.unwrapping[n]; ok {
+		return nil, nil
+	}
+
+	vo := g.getValueObject(n)
+	if vo == nil || vo.fields.NumFields() != 1 {
+		return nil, nil
+	}
+
+	value := vo.fields.Field(0)
+	return n, &FieldMatchWrapper{
+		Value:       value,
+		Accessor:    vo.accessors[value],
+		Constructor: vo.constructor,
+	}
+}
+
+// getNewtypeValue возвращает поле значения если данный тип является структурой-обёрткой. Такая структура
+// должна иметь единственное публичное поле. Непубличные поля допускаются только у сообщений protobuf, либо
+// если имеется конструктор обёртки. Тип уже разворачиваемый выше по вложенности не подходит.
+func (g *Generator) getNewtypeValue(t types.Type) (*types.Named, *types.Var) {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return nil, nil
+	}
+
+	if _, ok := g.unwrapping[n]; ok {
+		return nil, nil
+	}
+
+	s, ok := n.Underlying().(*types.Struct)
+	if !ok {
+		return nil, nil
+	}
+
+	var value *types.Var
+	var tag string
+	var private bool
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		switch {
+		case isProtoServiceField(f):
+		case !f.Exported():
+			private = true
+		case value != nil:
+			return nil, nil
+		default:
+			value = f
+			tag = s.Tag(i)
+		}
+	}
+
+	if value == nil || value.Embedded() {
+		return nil, nil
+	}
+
+	if private {
+		if _, isProto := reflect.StructTag(tag).Lookup("protobuf"); !isProto && g.getNewtypeConstructor(n, value) == nil {
+			return nil, nil
+		}
+	}
+
+	return n, value
+}
+
+// getNewtypeConstructor возвращает конструктор обёртки New<Type>(value) вида func(V) T, func(V) *T,
+// func(V) (T, error) или func(V) (*T, error), либо nil если его нет или он недоступен из пакета
+// primary-структуры.
+func (g *Generator) getNewtypeConstructor(n *types.Named, value *types.Var) *types.Func {
+	if n.Obj().Pkg() == nil {
+		return nil
+	}
+
+	fn, ok := n.Obj().Pkg().Scope().Lookup("New" + n.Obj().Name()).(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	if !fn.Exported() && fn.Pkg().Path() != g.outPath {
+		return nil
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Variadic() || !types.AssignableTo(value.Type(), sig.Params().At(0).Type()) {
+		return nil
+	}
+
+	switch sig.Results().Len() {
+	case 2:
+		if sig.Results().At(1).Type().String() != "error" {
+			return nil
+		}
+		fallthrough
+	case 1:
+		if !types.Identical(unpointer(sig.Results().At(0).Type()), n) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	return fn
+}
@@ -0,0 +1,132 @@
+package generator
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/sirkon/gogh"
+)
+
+// fieldNames возвращает нормализованные имена поля f структуры s по которым ведётся сопоставление.
+// По умолчанию это gogh.Underscored от имени поля. В режиме protoNames для полей сгенерированных protoc-gen-go
+// используются имя поля из описания protobuf и его json_name, взятые из тега protobuf:"...".
+func (g *Generator) fieldNames(s *types.Struct, f *types.Var) []string {
+	if !g.protoNames {
+		return []string{gogh.Underscored(f.Name())}
+	}
+
+	name, json, ok := protoFieldNames(s, f)
+	if !ok {
+		return []string{gogh.Underscored(f.Name())}
+	}
+
+	res := []string{gogh.Underscored(name)}
+	if json != "" {
+		res = append(res, gogh.Underscored(json))
+	}
+
+	return res
+}
+
+// fieldNamesMatch проверка, что имена полей сопоставимы
+func fieldNamesMatch(x, y []string) bool {
+	for _, a := range x {
+		for _, b := range y {
+			if a == b {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isSkippedField поля исключаемые из сопоставления и отчёта о несопоставленных полях
+func (g *Generator) isSkippedField(s *types.Struct, f *types.Var) bool {
+	if isProtoServiceField(f) || isProtoInternalField(s, f) {
+		return true
+	}
+
+	if !g.protoNames {
+		return false
+	}
+
+	if _, _, ok := protoFieldNames(s, f); !ok {
+		return false
+	}
+
+	return g.isDeprecatedField(f)
+}
+
+// protoFieldNames достаёт имя поля в protobuf и json_name из тега поля
+func protoFieldNames(s *types.Struct, f *types.Var) (name string, json string, ok bool) {
+	value, ok := reflect.StructTag(fieldTag(s, f)).Lookup("protobuf")
+	if !ok {
+		return "", "", false
+	}
+
+	for _, part := range strings.Split(value, ",") {
+		switch {
+		case strings.HasPrefix(part, "name="):
+			name = strings.TrimPrefix(part, "name=")
+		case strings.HasPrefix(part, "json="):
+			json = strings.TrimPrefix(part, "json=")
+		}
+	}
+
+	if name == "" {
+		return "", "", false
+	}
+
+	return name, json, true
+}
+
+// isDeprecatedField поле помечено как deprecated. protoc-gen-go оставляет комментарий
+// "Deprecated: ..." над полем либо справа от него, его и ищем.
+func (g *Generator) isDeprecatedField(f *types.Var) bool {
+	if f.Pkg() == nil {
+		return false
+	}
+
+	var deprecated bool
+	for _, file := range g.syntax[f.Pkg().Path()] {
+		if file.Pos() > f.Pos() || file.End() < f.Pos() {
+			continue
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			field, ok := node.(*ast.Field)
+			if !ok {
+				return !deprecated
+			}
+
+			for _, name := range field.Names {
+				if name.Pos() != f.Pos() {
+					continue
+				}
+
+				deprecated = hasDeprecationNote(field.Doc) || hasDeprecationNote(field.Comment)
+			}
+
+			return !deprecated
+		})
+	}
+
+	return deprecated
+}
+
+func hasDeprecationNote(group *ast.CommentGroup) bool {
+	if group == nil {
+		return false
+	}
+
+	for _, line := range strings.Split(group.Text(), "\n") {
+		if strings.HasPrefix(line, "Deprecated:") {
+			return true
+		}
+	}
+
+	return false
+}
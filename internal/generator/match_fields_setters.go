@@ -0,0 +1,131 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+)
+
+// getSettersMatches поиск сеттеров приёмника для полей источника, которые не были сопоставлены никаким полям.
+// Сеттер Set<Field>(v) должен иметь единственный параметр, а возвращать либо ничего, либо ошибку, либо сам
+// приёмник (сеттеры построителей). Тип поля должен быть эквивалентен типу параметра.
+func (g *Generator) getSettersMatches(matches []fieldMatchInfo, oos []fieldSecondaryOneof) []fieldMethodMatch {
+	var res []fieldMethodMatch
+
+	// поля primary-типа передаваемые сеттерам secondary-типа
+	if g.secVO == nil {
+		for _, m := range matches {
+			if _, ok := m.descr.(*FieldMatchNoMatch); !ok {
+				continue
+			}
+			if _, ok := g.xclude[m.prim.Name()]; ok {
+				continue
+			}
+
+			setter := lookForSetter(g.sec, m.prim.Name())
+			if setter == nil {
+				continue
+			}
+
+			param := setter.Type().(*types.Signature).Params().At(0).Type()
+			descr := g.getFieldTypeMatchDescription(m.prim, m.prim.Type(), param)
+			if _, ok := descr.(*FieldMatchNoMatch); ok {
+				continue
+			}
+
+			res = append(res, fieldMethodMatch{
+				primary: false,
+				setter:  true,
+				method:  setter,
+				field:   m.prim,
+				descr:   descr,
+			})
+		}
+	}
+
+	// поля secondary-типа передаваемые сеттерам primary-типа
+	if g.primVO == nil {
+		sec := g.structOf(g.sec)
+		for i := 0; i < sec.NumFields(); i++ {
+			f := sec.Field(i)
+			if !g.isAccessible(f) || g.isSkippedField(sec, f) || g.secondaryFieldCovered(f, matches, oos) {
+				continue
+			}
+
+			setter := lookForSetter(g.prim, f.Name())
+			if setter == nil {
+				continue
+			}
+
+			// настройки полей задаются по именам primary-полей, для сеттера используется имя устанавливаемого поля
+			param := setter.Type().(*types.Signature).Params().At(0).Type()
+			pf := types.NewVar(setter.Pos(), setter.Pkg(), f.Name(), param)
+			descr := g.getFieldTypeMatchDescription(pf, param, f.Type())
+			if _, ok := descr.(*FieldMatchNoMatch); ok {
+				continue
+			}
+
+			res = append(res, fieldMethodMatch{
+				primary: true,
+				setter:  true,
+				method:  setter,
+				field:   f,
+				descr:   descr,
+			})
+		}
+	}
+
+	return res
+}
+
+// fieldSetter сеттер поля field типа owner, через который значение записывается вместо прямого присваивания.
+// Типы-значения строятся конструктором, сеттеры для них не используются.
+func (g *Generator) fieldSetter(owner types.Type, field *types.Var) *types.Func {
+	if g.valueObjectOf(owner) != nil {
+		return nil
+	}
+
+	n, ok := owner.(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	setter := lookForSetter(n, field.Name())
+	if setter == nil {
+		return nil
+	}
+
+	if !types.Identical(setter.Type().(*types.Signature).Params().At(0).Type(), field.Type()) {
+		return nil
+	}
+
+	return setter
+}
+
+// lookForSetter поиск публичного сеттера Set<Field> с единственным параметром
+func lookForSetter(n *types.Named, field string) *types.Func {
+	mset := types.NewMethodSet(types.NewPointer(n))
+	for i := 0; i < mset.Len(); i++ {
+		m := mset.At(i).Obj().(*types.Func)
+		if !m.Exported() || gogh.Underscored(m.Name()) != "set_"+gogh.Underscored(field) {
+			continue
+		}
+
+		sig := m.Type().(*types.Signature)
+		if sig.Params().Len() != 1 || sig.Variadic() {
+			continue
+		}
+
+		switch sig.Results().Len() {
+		case 0:
+			return m
+		case 1:
+			res := sig.Results().At(0).Type()
+			if res.String() == "error" || types.Identical(unpointer(res), n) {
+				return m
+			}
+		}
+	}
+
+	return nil
+}
@@ -15,7 +15,7 @@ const (
 	sliceMatchStateMatched
 )
 
-func (g *Generator) areEquivalentSlices(prim, sec types.Type) (*FieldMatchSlice, sliceMatchState) {
+func (g *Generator) areEquivalentSlices(policy fieldPolicy, prim, sec types.Type) (*FieldMatchSlice, sliceMatchState) {
 	p, pok := prim.(*types.Slice)
 	s, sok := sec.(*types.Slice)
 
@@ -27,7 +27,7 @@ func (g *Generator) areEquivalentSlices(prim, sec types.Type) (*FieldMatchSlice,
 		return nil, sliceMatchStateIncompatibleWithSlice
 	}
 
-	x := g.getTypeMatchDescription(p.Elem(), s.Elem())
+	x := g.getTypeMatchDescription(policy, p.Elem(), s.Elem())
 	switch v := x.(type) {
 	case *FieldMatchNoMatch:
 		return nil, sliceMatchStateDifferentSlices
@@ -0,0 +1,107 @@
+package generator
+
+import "go/types"
+
+// matchSQL сопоставление типа реализующего driver.Valuer (и sql.Scanner для указателя на него) с примитивом,
+// который может вернуть метод Value. Какой именно примитив он вернёт статически не известно, поэтому сопоставление
+// делается только если примитив закреплён для поля, либо он единственный подходящий судя по устройству типа.
+// Указатели на данном этапе уже сняты.
+func (g *Generator) matchSQL(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	if isDriverValueType(sec) && isSQLValueType(prim) && (policy.sql || isSQLValueCandidate(prim, sec)) {
+		return &FieldMatchSQL{
+			Secondary: false,
+		}, true
+	}
+
+	if isDriverValueType(prim) && isSQLValueType(sec) && (policy.sql || isSQLValueCandidate(sec, prim)) {
+		return &FieldMatchSQL{
+			Secondary: true,
+		}, true
+	}
+
+	return nil, false
+}
+
+// isSQLValueType тип реализует driver.Valuer, а указатель на него — sql.Scanner
+func isSQLValueType(t types.Type) bool {
+	if _, ok := t.(*types.Named); !ok || types.IsInterface(t) {
+		return false
+	}
+
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Value")
+	value, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := value.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 2 {
+		return false
+	}
+	if sig.Results().At(0).Type().String() != "database/sql/driver.Value" {
+		return false
+	}
+	if sig.Results().At(1).Type().String() != "error" {
+		return false
+	}
+
+	obj, _, _ = types.LookupFieldOrMethod(types.NewPointer(t), true, nil, "Scan")
+	scan, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig = scan.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || sig.Results().At(0).Type().String() != "error" {
+		return false
+	}
+
+	param, ok := unalias(sig.Params().At(0).Type()).Underlying().(*types.Interface)
+	return ok && param.Empty()
+}
+
+// isDriverValueType тип является одним из тех, что может вернуть driver.Valuer: int64, float64, bool, []byte,
+// string или time.Time. Значение приводится к нему проверкой типа, поэтому именованные типы не подходят.
+func isDriverValueType(t types.Type) bool {
+	switch v := unalias(t).(type) {
+	case *types.Basic:
+		switch v.Kind() {
+		case types.Int64, types.Float64, types.Bool, types.String:
+			return true
+		}
+	case *types.Slice:
+		if b, ok := unalias(v.Elem()).(*types.Basic); ok {
+			return b.Kind() == types.Byte
+		}
+	case *types.Named:
+		return v.Obj().Pkg() != nil && v.Obj().Pkg().Path() == "time" && v.Obj().Name() == "Time"
+	}
+
+	return false
+}
+
+// isSQLValueCandidate примитив value единственный, который может вернуть метод Value типа t судя по устройству
+// последнего: это нижележащий тип t, либо t является структурой с единственным полем подходящего типа.
+func isSQLValueCandidate(t, value types.Type) bool {
+	var candidate types.Type
+	switch v := t.Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < v.NumFields(); i++ {
+			f := v.Field(i)
+			if !isDriverValueType(f.Type()) {
+				continue
+			}
+
+			if candidate != nil {
+				return false
+			}
+			candidate = f.Type()
+		}
+	default:
+		if isDriverValueType(v) {
+			candidate = v
+		}
+	}
+
+	return candidate != nil && types.Identical(candidate, value)
+}
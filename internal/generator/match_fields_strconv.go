@@ -0,0 +1,49 @@
+package generator
+
+import "go/types"
+
+// matchStrconv сопоставление строк с числами и логическими значениями через strconv, работает только если такие
+// конвертации разрешены для поля. Указатели на данном этапе уже сняты.
+func (g *Generator) matchStrconv(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	if !policy.strconv {
+		return nil, false
+	}
+
+	if isStringKind(prim) && strconvKind(sec) != nil {
+		return &FieldMatchStrconv{
+			Secondary: false,
+		}, true
+	}
+
+	if isStringKind(sec) && strconvKind(prim) != nil {
+		return &FieldMatchStrconv{
+			Secondary: true,
+		}, true
+	}
+
+	return nil, false
+}
+
+func isStringKind(t types.Type) bool {
+	v, ok := t.Underlying().(*types.Basic)
+	return ok && v.Kind() == types.String
+}
+
+// strconvKind возвращает базовый тип числа или логического значения, которое разбирается и форматируется
+// функциями strconv, либо nil если тип не подходит.
+func strconvKind(t types.Type) *types.Basic {
+	v, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+
+	switch v.Kind() {
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+		types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64,
+		types.Float32, types.Float64,
+		types.Bool:
+		return v
+	default:
+		return nil
+	}
+}
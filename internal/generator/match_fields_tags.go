@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/sirkon/gogh"
+)
+
+// lookForSecondaryField поиск поля в secondary-структуре сопоставимого по имени полю pf из primary.
+// Если задан ключ тега, то вначале ищется поле с совпадающим значением тега и только после этого
+// происходит сопоставление по именам полей.
+func (g *Generator) lookForSecondaryField(
+	prim *types.Struct,
+	pf *types.Var,
+	sec *types.Struct,
+	wants []string,
+) *types.Var {
+	if ptag := g.fieldTagName(prim, pf); ptag != "" {
+		for i := 0; i < sec.NumFields(); i++ {
+			ps := sec.Field(i)
+			if !g.isAccessible(ps) || g.isSkippedField(sec, ps) {
+				continue
+			}
+
+			if g.fieldTagName(sec, ps) == ptag {
+				return ps
+			}
+		}
+	}
+
+	for i := 0; i < sec.NumFields(); i++ {
+		ps := sec.Field(i)
+		if !g.isAccessible(ps) || g.isSkippedField(sec, ps) {
+			continue
+		}
+
+		if fieldNamesMatch(wants, g.fieldNames(sec, ps)) {
+			return ps
+		}
+	}
+
+	return nil
+}
+
+// lookForMappedField поиск поля secondary-структуры, которому вручную сопоставлено поле primary. name это
+// gogh.Underscored имени поля.
+func (g *Generator) lookForMappedField(sec *types.Struct, name string) *types.Var {
+	for i := 0; i < sec.NumFields(); i++ {
+		ps := sec.Field(i)
+		if !g.isAccessible(ps) || g.isSkippedField(sec, ps) {
+			continue
+		}
+
+		if gogh.Underscored(ps.Name()) == name {
+			return ps
+		}
+	}
+
+	return nil
+}
+
+// hasField проверка, что в структуре s есть поле, имя которого сопоставимо с name
+func hasField(s *types.Struct, name string) bool {
+	for i := 0; i < s.NumFields(); i++ {
+		if gogh.Underscored(s.Field(i).Name()) == gogh.Underscored(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isMappedField проверка, что полю ps secondary-структуры вручную сопоставлено какое-то поле primary
+func isMappedField(manual map[string]string, ps *types.Var) bool {
+	name := gogh.Underscored(ps.Name())
+	for _, v := range manual {
+		if v == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldsMatch проверка, что поля x и y структур xs и ys соответственно сопоставимы по тегу либо по имени
+func (g *Generator) fieldsMatch(xs *types.Struct, x *types.Var, ys *types.Struct, y *types.Var) bool {
+	if xtag := g.fieldTagName(xs, x); xtag != "" && xtag == g.fieldTagName(ys, y) {
+		return true
+	}
+
+	return fieldNamesMatch(g.fieldNames(xs, x), g.fieldNames(ys, y))
+}
+
+// fieldTagName нормализованное имя из тега поля с ключом tagKey. Пустая строка если ключ не задан, тега
+// нет или поле исключено тегом ("-").
+func (g *Generator) fieldTagName(s *types.Struct, f *types.Var) string {
+	if g.tagKey == "" {
+		return ""
+	}
+
+	value, ok := reflect.StructTag(fieldTag(s, f)).Lookup(g.tagKey)
+	if !ok {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(value, ",")
+	if name == "" || name == "-" {
+		return ""
+	}
+
+	return gogh.Underscored(name)
+}
+
+// fieldTag возвращает тег поля f структуры s
+func fieldTag(s *types.Struct, f *types.Var) string {
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i) == f {
+			return s.Tag(i)
+		}
+	}
+
+	return ""
+}
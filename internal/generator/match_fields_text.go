@@ -0,0 +1,101 @@
+package generator
+
+import "go/types"
+
+// matchText сопоставление типа имеющего текстовое представление со строками и []byte. Тип должен
+// реализовывать encoding.TextMarshaler либо fmt.Stringer, а указатель на него — encoding.TextUnmarshaler.
+// Указатели на данном этапе уже сняты.
+func (g *Generator) matchText(prim, sec types.Type) (FieldMatchDescription, bool) {
+	if isTextual(sec) {
+		if marshal, ok := getTextEncoding(prim); ok {
+			return &FieldMatchText{
+				Secondary: false,
+				Stringer:  !marshal,
+			}, true
+		}
+	}
+
+	if isTextual(prim) {
+		if marshal, ok := getTextEncoding(sec); ok {
+			return &FieldMatchText{
+				Secondary: true,
+				Stringer:  !marshal,
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// getTextEncoding проверка, что значение данного типа может быть переведено в текст и обратно. marshal
+// показывает, что для перевода в текст используется MarshalText, а не String. Время и продолжительности
+// переводятся в строки только согласно заданному для поля представлению (см. matchTime).
+func getTextEncoding(t types.Type) (marshal bool, ok bool) {
+	if _, ok := t.(*types.Named); !ok {
+		return false, false
+	}
+	if _, ok := isTimeType(t); ok {
+		return false, false
+	}
+
+	bytes := types.NewSlice(types.Typ[types.Byte])
+	errType := types.Universe.Lookup("error").Type()
+	if !hasMethodSig(types.NewPointer(t), "UnmarshalText", []types.Type{bytes}, []types.Type{errType}) {
+		return false, false
+	}
+
+	if hasMethodSig(types.NewPointer(t), "MarshalText", nil, []types.Type{bytes, errType}) {
+		return true, true
+	}
+
+	if hasMethodSig(types.NewPointer(t), "String", nil, []types.Type{types.Typ[types.String]}) {
+		return false, true
+	}
+
+	return false, false
+}
+
+// isTextual тип является строкой или слайсом байт, возможно именованными
+func isTextual(t types.Type) bool {
+	switch v := t.Underlying().(type) {
+	case *types.Basic:
+		return v.Kind() == types.String
+	case *types.Slice:
+		return isByte(v.Elem())
+	default:
+		return false
+	}
+}
+
+func isByte(t types.Type) bool {
+	v, ok := t.Underlying().(*types.Basic)
+	return ok && v.Kind() == types.Byte
+}
+
+// hasMethodSig проверка наличия у типа t метода с данными типами параметров и возвращаемых значений
+func hasMethodSig(t types.Type, name string, params []types.Type, results []types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name)
+	m, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+
+	sig := m.Type().(*types.Signature)
+	if sig.Params().Len() != len(params) || sig.Results().Len() != len(results) || sig.Variadic() {
+		return false
+	}
+
+	for i, p := range params {
+		if !types.Identical(sig.Params().At(i).Type(), p) {
+			return false
+		}
+	}
+
+	for i, r := range results {
+		if !types.Identical(sig.Results().At(i).Type(), r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,260 @@
+package generator
+
+import (
+	"go/types"
+	"reflect"
+	"strings"
+
+	"github.com/sirkon/gogh"
+)
+
+// thriftUnion описание объединения сгенерированного Thrift. Это структура полей-указателей, для каждого поля
+// которой имеется метод IsSet<Field>() bool, а сама структура имеет метод CountSetFields<Name>() int.
+type thriftUnion struct {
+	typ    *types.Named
+	fields []*types.Var
+}
+
+// getThriftUnion возвращает описание Thrift-объединения если данный тип является таковым
+func getThriftUnion(t types.Type) *thriftUnion {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	s, ok := n.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	if !hasNoArgMethod(n, "CountSetFields"+n.Obj().Name(), types.Typ[types.Int]) {
+		return nil
+	}
+
+	res := &thriftUnion{
+		typ: n,
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Exported() {
+			continue
+		}
+
+		if thriftIsSet(n, f) == "" {
+			return nil
+		}
+
+		res.fields = append(res.fields, f)
+	}
+
+	if len(res.fields) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// thriftIsSet возвращает название метода IsSet<Field> проверки наличия значения поля в структуре
+// сгенерированной Thrift, либо пустую строку если такого метода нет.
+func thriftIsSet(owner types.Type, field *types.Var) string {
+	n, ok := unpointer(owner).(*types.Named)
+	if !ok {
+		return ""
+	}
+
+	name := "IsSet" + field.Name()
+	if !hasNoArgMethod(n, name, types.Typ[types.Bool]) {
+		return ""
+	}
+
+	return name
+}
+
+// hasNoArgMethod проверка наличия метода без параметров с единственным возвращаемым значением данного типа
+func hasNoArgMethod(n *types.Named, name string, res types.Type) bool {
+	m := lookForMethod(n, name)
+	if m == nil {
+		return false
+	}
+
+	sig := m.Type().(*types.Signature)
+	if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+		return false
+	}
+
+	return types.Identical(sig.Results().At(0).Type(), res)
+}
+
+// getSealedImpls возвращает реализации "закрытого" интерфейса: интерфейс должен иметь хотя бы один
+// неэкспортируемый метод, тогда реализовать его могут только типы из того же пакета. Oneof-интерфейсы
+// protoc-gen-go так же являются закрытыми, их реализации — обёртки ветвей.
+func getSealedImpls(t types.Type) []types.Type {
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() == nil {
+		return nil
+	}
+
+	iface, ok := n.Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+
+	var sealed bool
+	for i := 0; i < iface.NumMethods(); i++ {
+		if !iface.Method(i).Exported() {
+			sealed = true
+			break
+		}
+	}
+	if !sealed {
+		return nil
+	}
+
+	var res []types.Type
+	scope := n.Obj().Pkg().Scope()
+	for _, name := range scope.Names() {
+		tn, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || tn.IsAlias() {
+			continue
+		}
+
+		impl, ok := tn.Type().(*types.Named)
+		if !ok || types.IsInterface(impl) {
+			continue
+		}
+
+		switch {
+		case types.Implements(impl, iface):
+			res = append(res, impl)
+		case types.Implements(types.NewPointer(impl), iface):
+			res = append(res, types.NewPointer(impl))
+		}
+	}
+
+	return res
+}
+
+// getOneofBranchValue возвращает поле со значением ветви если данная реализация oneof-интерфейса является
+// обёрткой ветви сгенерированной protoc-gen-go.
+func getOneofBranchValue(impl types.Type) *types.Var {
+	s, ok := unpointer(impl).Underlying().(*types.Struct)
+	if !ok || s.NumFields() != 1 {
+		return nil
+	}
+
+	value, ok := reflect.StructTag(s.Tag(0)).Lookup("protobuf")
+	if !ok || !strings.HasSuffix(value, ",oneof") {
+		return nil
+	}
+
+	return s.Field(0)
+}
+
+// matchUnion сопоставление Thrift-объединения с oneof-полем protobuf или закрытым интерфейсом Go.
+// Указатели на данном этапе уже сняты.
+func (g *Generator) matchUnion(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	if union := getThriftUnion(prim); union != nil {
+		branches, ok := g.matchUnionBranches(policy, union, sec, false)
+		if !ok {
+			return nil, false
+		}
+
+		return &FieldMatchUnion{
+			Secondary: false,
+			Branches:  branches,
+		}, true
+	}
+
+	if union := getThriftUnion(sec); union != nil {
+		branches, ok := g.matchUnionBranches(policy, union, prim, true)
+		if !ok {
+			return nil, false
+		}
+
+		return &FieldMatchUnion{
+			Secondary: true,
+			Branches:  branches,
+		}, true
+	}
+
+	return nil, false
+}
+
+// matchUnionBranches сопоставление полей объединения с реализациями интерфейса. Сопоставление должно быть
+// взаимно однозначным. Ветвь oneof сопоставляется полю объединения по имени поля в обёртке, реализация закрытого
+// интерфейса — по имени типа, содержащего в себе имя поля, либо, если таковой нет, по единственному
+// подходящему типу.
+func (g *Generator) matchUnionBranches(
+	policy fieldPolicy,
+	union *thriftUnion,
+	other types.Type,
+	unionIsSecondary bool,
+) ([]*FieldMatchUnionBranch, bool) {
+	impls := getSealedImpls(other)
+	if len(impls) != len(union.fields) {
+		return nil, false
+	}
+
+	match := func(field *types.Var, value types.Type) FieldMatchDescription {
+		if unionIsSecondary {
+			return g.getTypeMatchDescription(policy, value, field.Type())
+		}
+
+		return g.getTypeMatchDescription(policy, field.Type(), value)
+	}
+
+	used := map[int]struct{}{}
+	var res []*FieldMatchUnionBranch
+	for _, field := range union.fields {
+		var candidates []int
+		named := -1
+		for i, impl := range impls {
+			if _, ok := used[i]; ok {
+				continue
+			}
+
+			value := impl
+			if v := getOneofBranchValue(impl); v != nil {
+				value = v.Type()
+				if gogh.Underscored(v.Name()) != gogh.Underscored(field.Name()) {
+					continue
+				}
+			}
+
+			if _, ok := match(field, value).(*FieldMatchNoMatch); ok {
+				continue
+			}
+
+			candidates = append(candidates, i)
+			if strings.Contains(
+				gogh.Underscored(unpointer(impl).(*types.Named).Obj().Name()),
+				gogh.Underscored(field.Name()),
+			) {
+				named = i
+			}
+		}
+
+		i := named
+		if i < 0 {
+			if len(candidates) != 1 {
+				return nil, false
+			}
+			i = candidates[0]
+		}
+
+		used[i] = struct{}{}
+		branch := &FieldMatchUnionBranch{
+			Field: field,
+			Impl:  impls[i],
+			Value: getOneofBranchValue(impls[i]),
+		}
+		if branch.Value != nil {
+			branch.Elem = match(field, branch.Value.Type())
+		} else {
+			branch.Elem = match(field, impls[i])
+		}
+		res = append(res, branch)
+	}
+
+	return res, true
+}
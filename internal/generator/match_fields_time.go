@@ -0,0 +1,136 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+	"time"
+
+	"github.com/sirkon/errors"
+)
+
+// Представления времени в виде целых чисел. Любое другое значение политики считается форматом для time.Format
+// либо названием константы формата из пакета time.
+const (
+	timePolicyUnix      = "unix"
+	timePolicyUnixMilli = "unix-ms"
+	timePolicyUnixNano  = "unix-ns"
+)
+
+// timeLayoutConsts константы форматов пакета time, ключами являются их названия в нижнем регистре
+var timeLayoutConsts = map[string]string{}
+
+func init() {
+	for _, name := range []string{
+		"Layout",
+		"ANSIC",
+		"UnixDate",
+		"RubyDate",
+		"RFC822",
+		"RFC822Z",
+		"RFC850",
+		"RFC1123",
+		"RFC1123Z",
+		"RFC3339",
+		"RFC3339Nano",
+		"Kitchen",
+		"Stamp",
+		"StampMilli",
+		"StampMicro",
+		"StampNano",
+		"DateTime",
+		"DateOnly",
+		"TimeOnly",
+	} {
+		timeLayoutConsts[strings.ToLower(name)] = name
+	}
+}
+
+// checkTimePolicy проверка корректности политики представления времени
+func checkTimePolicy(policy string) error {
+	switch policy {
+	case timePolicyUnix, timePolicyUnixMilli, timePolicyUnixNano:
+		return nil
+	}
+
+	if _, ok := timeLayoutConsts[strings.ToLower(policy)]; ok {
+		return nil
+	}
+
+	// формат без элементов даты и времени переводится в текст сам в себя
+	if time.Unix(0, 0).UTC().Format(policy) == policy {
+		return errors.Newf("'%s' is neither unix, unix-ms, unix-ns nor a time layout", policy)
+	}
+
+	return nil
+}
+
+// isUnixTimePolicy политика представляет время целым числом
+func isUnixTimePolicy(policy string) bool {
+	switch policy {
+	case timePolicyUnix, timePolicyUnixMilli, timePolicyUnixNano:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchTime сопоставление time.Time и time.Duration с целыми числами или строками согласно политике
+// представления времени поля. Указатели на данном этапе уже сняты.
+func (g *Generator) matchTime(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	if policy.time == "" {
+		return nil, false
+	}
+
+	if duration, ok := isTimeType(prim); ok && isTimePrimitive(sec, policy.time) {
+		return &FieldMatchTime{
+			Secondary: false,
+			Duration:  duration,
+			Policy:    policy.time,
+		}, true
+	}
+
+	if duration, ok := isTimeType(sec); ok && isTimePrimitive(prim, policy.time) {
+		return &FieldMatchTime{
+			Secondary: true,
+			Duration:  duration,
+			Policy:    policy.time,
+		}, true
+	}
+
+	return nil, false
+}
+
+// isTimeType тип является time.Time либо time.Duration, duration показывает что это именно time.Duration
+func isTimeType(t types.Type) (duration bool, ok bool) {
+	n, isNamed := t.(*types.Named)
+	if !isNamed || n.Obj().Pkg() == nil || n.Obj().Pkg().Path() != "time" {
+		return false, false
+	}
+
+	switch n.Obj().Name() {
+	case "Time":
+		return false, true
+	case "Duration":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// isTimePrimitive тип подходит для представления времени согласно политике policy
+func isTimePrimitive(t types.Type, policy string) bool {
+	if _, ok := isTimeType(t); ok {
+		return false
+	}
+
+	v, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return false
+	}
+
+	if isUnixTimePolicy(policy) {
+		return v.Info()&types.IsInteger != 0
+	}
+
+	return v.Kind() == types.String
+}
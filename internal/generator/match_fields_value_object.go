@@ -0,0 +1,146 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/sirkon/gogh"
+)
+
+// valueObject тип-значение с непубличными полями. Значения такого типа читаются публичными методами без
+// параметров, а строятся конструктором New<Type>, параметры которого сопоставляются этим методам по именам.
+type valueObject struct {
+	constructor *types.Func
+	// fields синтетические поля: по одному на параметр конструктора в порядке параметров
+	fields *types.Struct
+	// accessors методы доступа к значениям синтетических полей
+	accessors map[*types.Var]*types.Func
+}
+
+// getValueObject возвращает описание типа-значения если данный тип является таковым. Тип должен быть
+// структурой без публичных полей, иметь доступный из пакета primary-структуры конструктор New<Type> вида
+// func(...) T, func(...) *T, func(...) (T, error) или func(...) (*T, error), а для каждого параметра
+// конструктора должен найтись метод доступа <Param>() или Get<Param>() возвращающий значение того же типа.
+func (g *Generator) getValueObject(n *types.Named) *valueObject {
+	s, ok := n.Underlying().(*types.Struct)
+	if !ok || s.NumFields() == 0 || n.Obj().Pkg() == nil {
+		return nil
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Exported() {
+			return nil
+		}
+	}
+
+	fn, ok := n.Obj().Pkg().Scope().Lookup("New" + n.Obj().Name()).(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	if !fn.Exported() && fn.Pkg().Path() != g.outPath {
+		return nil
+	}
+
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() == 0 || sig.Variadic() {
+		return nil
+	}
+
+	switch sig.Results().Len() {
+	case 2:
+		if sig.Results().At(1).Type().String() != "error" {
+			return nil
+		}
+		fallthrough
+	case 1:
+		if !types.Identical(unpointer(sig.Results().At(0).Type()), n) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	var fields []*types.Var
+	accessors := map[*types.Var]*types.Func{}
+	for i := 0; i < sig.Params().Len(); i++ {
+		param := sig.Params().At(i)
+		accessor := getValueObjectAccessor(n, param)
+		if accessor == nil {
+			return nil
+		}
+
+		field := types.NewField(param.Pos(), n.Obj().Pkg(), gogh.Public(param.Name()), param.Type(), false)
+		fields = append(fields, field)
+		accessors[field] = accessor
+	}
+
+	return &valueObject{
+		constructor: fn,
+		fields:      types.NewStruct(fields, nil),
+		accessors:   accessors,
+	}
+}
+
+// getValueObjectAccessor поиск метода доступа к значению соответствующему параметру конструктора
+func getValueObjectAccessor(n *types.Named, param *types.Var) *types.Func {
+	if param.Name() == "" || param.Name() == "_" {
+		return nil
+	}
+
+	want := gogh.Underscored(param.Name())
+	mset := types.NewMethodSet(types.NewPointer(n))
+	for i := 0; i < mset.Len(); i++ {
+		m := mset.At(i).Obj().(*types.Func)
+		if !m.Exported() {
+			continue
+		}
+
+		name := gogh.Underscored(m.Name())
+		if name != want && name != "get_"+want {
+			continue
+		}
+
+		sig := m.Type().(*types.Signature)
+		if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+			continue
+		}
+
+		if types.Identical(sig.Results().At(0).Type(), param.Type()) {
+			return m
+		}
+	}
+
+	return nil
+}
+
+// structOf поля типа участвующие в сопоставлении: синтетические поля для типов-значений и собственные поля
+// структуры для остальных
+func (g *Generator) structOf(n *types.Named) *types.Struct {
+	if vo := g.valueObjectOf(n); vo != nil {
+		return vo.fields
+	}
+
+	return n.Underlying().(*types.Struct)
+}
+
+// valueObjectOf описание типа-значения для primary- или secondary-типа
+func (g *Generator) valueObjectOf(t types.Type) *valueObject {
+	switch t {
+	case g.prim:
+		return g.primVO
+	case g.sec:
+		return g.secVO
+	default:
+		return nil
+	}
+}
+
+// fieldDestination выражение в которое записывается значение поля field типа owner при построении результата.
+// Для типов-значений это аргумент конструктора.
+func (g *Generator) fieldDestination(owner types.Type, field *types.Var) string {
+	if g.valueObjectOf(owner) != nil {
+		return "arg" + field.Name()
+	}
+
+	return "res." + field.Name()
+}
@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"go/types"
+	"strings"
+)
+
+const (
+	protoTimestampPkg = "google.golang.org/protobuf/types/known/timestamppb"
+	protoDurationPkg  = "google.golang.org/protobuf/types/known/durationpb"
+	protoWrappersPkg  = "google.golang.org/protobuf/types/known/wrapperspb"
+	gogoTypesPkg      = "github.com/gogo/protobuf/types"
+)
+
+// wellKnownType описание конвертации well-known типа protobuf в стандартный тип Go и обратно
+type wellKnownType struct {
+	pkg  string
+	name string
+	// std тип Go соответствующий well-known типу
+	std string
+	// method метод well-known типа возвращающий значение std
+	method string
+	// fromProto функция пакета well-known типа конвертирующая его в std
+	fromProto string
+	// toProto функция пакета well-known типа строящая его значение из std
+	toProto string
+}
+
+// wellKnownTypes поддерживаемые well-known типы, gogo-типы сопоставляются так же как их аналоги из golang/protobuf
+var wellKnownTypes = []wellKnownType{
+	{
+		pkg:     protoTimestampPkg,
+		name:    "Timestamp",
+		std:     "time.Time",
+		method:  "AsTime",
+		toProto: "New",
+	},
+	{
+		pkg:     protoDurationPkg,
+		name:    "Duration",
+		std:     "time.Duration",
+		method:  "AsDuration",
+		toProto: "New",
+	},
+	{
+		pkg:       gogoTypesPkg,
+		name:      "Timestamp",
+		std:       "time.Time",
+		fromProto: "TimestampFromProto",
+		toProto:   "TimestampProto",
+	},
+	{
+		pkg:       gogoTypesPkg,
+		name:      "Duration",
+		std:       "time.Duration",
+		fromProto: "DurationFromProto",
+		toProto:   "DurationProto",
+	},
+}
+
+// wrapperTypes названия типов-обёрток над примитивными значениями из wrappers.proto
+var wrapperTypes = map[string]struct{}{
+	"DoubleValue": {},
+	"FloatValue":  {},
+	"Int64Value":  {},
+	"UInt64Value": {},
+	"Int32Value":  {},
+	"UInt32Value": {},
+	"BoolValue":   {},
+	"StringValue": {},
+	"BytesValue":  {},
+}
+
+// matchWellKnown сопоставление well-known типов protobuf со стандартными типами Go. Указатели на данном
+// этапе уже сняты.
+func (g *Generator) matchWellKnown(policy fieldPolicy, prim, sec types.Type) (FieldMatchDescription, bool) {
+	if wkt := getWellKnownType(sec); wkt != nil && types.TypeString(prim, nil) == wkt.std {
+		return &FieldMatchConversion{
+			MethodSecondary:      wkt.method,
+			SecondaryToPrimary:   wkt.fromProto,
+			SecondaryFromPrimary: wkt.toProto,
+		}, true
+	}
+
+	if wkt := getWellKnownType(prim); wkt != nil && types.TypeString(sec, nil) == wkt.std {
+		return &FieldMatchConversion{
+			MethodPrimary:        wkt.method,
+			PrimaryToSecondary:   wkt.fromProto,
+			PrimaryFromSecondary: wkt.toProto,
+		}, true
+	}
+
+	if value := getWrapperValue(sec); value != nil && getWrapperValue(prim) == nil {
+		elem := g.getTypeMatchDescription(policy, prim, value.Type())
+		if _, ok := elem.(*FieldMatchNoMatch); ok {
+			return nil, false
+		}
+
+		return &FieldMatchWrapper{
+			Secondary: true,
+			Value:     value,
+			Elem:      elem,
+		}, true
+	}
+
+	if value := getWrapperValue(prim); value != nil && getWrapperValue(sec) == nil {
+		elem := g.getTypeMatchDescription(policy, value.Type(), sec)
+		if _, ok := elem.(*FieldMatchNoMatch); ok {
+			return nil, false
+		}
+
+		return &FieldMatchWrapper{
+			Secondary: false,
+			Value:     value,
+			Elem:      elem,
+		}, true
+	}
+
+	return nil, false
+}
+
+func getWellKnownType(t types.Type) *wellKnownType {
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() == nil {
+		return nil
+	}
+
+	for i, wkt := range wellKnownTypes {
+		if n.Obj().Pkg().Path() == wkt.pkg && n.Obj().Name() == wkt.name {
+			return &wellKnownTypes[i]
+		}
+	}
+
+	return nil
+}
+
+// getWrapperValue возвращает поле Value если данный тип является обёрткой из wrappers.proto
+func getWrapperValue(t types.Type) *types.Var {
+	n, ok := t.(*types.Named)
+	if !ok || n.Obj().Pkg() == nil {
+		return nil
+	}
+
+	switch n.Obj().Pkg().Path() {
+	case protoWrappersPkg, gogoTypesPkg:
+	default:
+		return nil
+	}
+
+	if _, ok := wrapperTypes[n.Obj().Name()]; !ok {
+		return nil
+	}
+
+	s, ok := n.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == "Value" {
+			return s.Field(i)
+		}
+	}
+
+	return nil
+}
+
+// isProtoServiceField служебные поля XXX_* генерируемые gogo/protobuf и старыми версиями golang/protobuf не
+// участвуют ни в сопоставлении, ни в отчёте о несопоставленных полях.
+func isProtoServiceField(f *types.Var) bool {
+	return strings.HasPrefix(f.Name(), "XXX_")
+}
+
+// protoInternalFields непубличные поля сообщений protoc-gen-go с состоянием сообщения. Они видны когда обе
+// структуры находятся в пакете сгенерированного кода, но в сопоставлении не участвуют.
+var protoInternalFields = map[string]struct{}{
+	"state":           {},
+	"sizeCache":       {},
+	"unknownFields":   {},
+	"extensionFields": {},
+	"weakFields":      {},
+}
+
+// isProtoInternalField проверка, что f является служебным полем сообщения protoc-gen-go. Сообщение опознаётся
+// по полю state с типом из google.golang.org/protobuf.
+func isProtoInternalField(s *types.Struct, f *types.Var) bool {
+	if _, ok := protoInternalFields[f.Name()]; !ok || f.Exported() {
+		return false
+	}
+
+	for i := 0; i < s.NumFields(); i++ {
+		state := s.Field(i)
+		if state.Name() != "state" {
+			continue
+		}
+
+		n, ok := state.Type().(*types.Named)
+		return ok && n.Obj().Pkg() != nil && strings.HasPrefix(n.Obj().Pkg().Path(), "google.golang.org/protobuf/")
+	}
+
+	return false
+}
@@ -4,7 +4,6 @@ import "go/types"
 
 func (g *Generator) getOneofImpls(scope *types.Scope, methodName string) []*types.Named {
 	var res []*types.Named
-outer:
 	for _, n := range scope.Names() {
 		item := scope.Lookup(n)
 		if !item.Exported() {
@@ -21,15 +20,17 @@ outer:
 			continue
 		}
 
-		// ищем магический метод
+		// ищем магический метод. Помимо него у ветви могут быть и другие методы — их добавляют, например,
+		// gogo/protobuf и vtprotobuf.
 		for i := 0; i < t.NumMethods(); i++ {
 			if t.Method(i).Name() != methodName {
-				continue outer
+				continue
 			}
-		}
 
-		// значит, эта структура относится к одной из ветвей
-		res = append(res, t)
+			// значит, эта структура относится к одной из ветвей
+			res = append(res, t)
+			break
+		}
 	}
 
 	return res
@@ -0,0 +1,23 @@
+package bh
+
+import "time"
+
+type News struct {
+	Title  string
+	Posted time.Time
+}
+
+type NewsRow struct {
+	Title  string
+	Posted string
+}
+
+type Address struct {
+	City   string
+	Street string
+}
+
+type AddressDTO struct {
+	City   string
+	Street string
+}
@@ -0,0 +1,19 @@
+package bh
+
+type User struct {
+	Name string
+}
+
+type UserDTO struct {
+	Name string
+}
+
+// UserSliceToUserDTOSlice is declared by the user, batch helpers of User must not clash with it.
+func UserSliceToUserDTOSlice(xs []User) []UserDTO {
+	res := make([]UserDTO, len(xs))
+	for i, x := range xs {
+		res[i] = UserDTO{Name: x.Name}
+	}
+
+	return res
+}
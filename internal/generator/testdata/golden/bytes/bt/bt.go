@@ -0,0 +1,21 @@
+package bt
+
+type Hash [32]byte
+
+type Domain struct {
+	Name  string
+	Blob  []byte
+	ID    [16]byte
+	Sum   *Hash
+	Key   []byte
+	Names []string
+}
+
+type Proto struct {
+	Name  []byte
+	Blob  *string
+	ID    []byte
+	Sum   []byte
+	Key   [4]byte
+	Names [][]byte
+}
@@ -0,0 +1,5 @@
+package model
+
+type Address struct {
+	City string
+}
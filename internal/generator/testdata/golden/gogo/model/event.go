@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type Event struct {
+	ID      string
+	Created time.Time
+	Comment string
+	Address *Address
+	User    *string
+	Group   *int64
+}
@@ -0,0 +1,44 @@
+package pb
+
+import (
+	types "github.com/gogo/protobuf/types"
+)
+
+type Address struct {
+	City                 string
+	XXX_NoUnkeyedLiteral struct{}
+	XXX_unrecognized     []byte
+	XXX_sizecache        int32
+}
+
+type Event struct {
+	Id                   string
+	Created              *types.Timestamp
+	Comment              *types.StringValue
+	Address              Address
+	Target               isEvent_Target
+	XXX_NoUnkeyedLiteral struct{}
+	XXX_unrecognized     []byte
+	XXX_sizecache        int32
+}
+
+type isEvent_Target interface {
+	isEvent_Target()
+	MarshalTo([]byte) (int, error)
+	Size() int
+}
+
+type Event_User struct {
+	User string `protobuf:"bytes,7,opt,name=user,proto3,oneof"`
+}
+
+type Event_Group struct {
+	Group int64 `protobuf:"varint,8,opt,name=group,proto3,oneof"`
+}
+
+func (*Event_User) isEvent_Target()                {}
+func (*Event_User) MarshalTo([]byte) (int, error)  { return 0, nil }
+func (*Event_User) Size() int                      { return 0 }
+func (*Event_Group) isEvent_Target()               {}
+func (*Event_Group) MarshalTo([]byte) (int, error) { return 0, nil }
+func (*Event_Group) Size() int                     { return 0 }
@@ -0,0 +1,13 @@
+package hk
+
+type User struct {
+	Name  string
+	Age   int
+	Email string
+}
+
+type UserDTO struct {
+	Name  string
+	Age   []int
+	Email []string
+}
@@ -0,0 +1,15 @@
+package hk
+
+// convertUserAgeToUserDTO conversion of field Age of User into field Age of UserDTO
+func convertUserAgeToUserDTO(v int) ([]int, error) {
+	return []int{v}, nil
+}
+
+// convertUserDTOAgeToUser conversion of field Age of UserDTO into field Age of User
+func convertUserDTOAgeToUser(v []int) (int, error) {
+	if len(v) == 0 {
+		return 0, nil
+	}
+
+	return v[0], nil
+}
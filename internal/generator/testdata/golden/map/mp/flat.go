@@ -0,0 +1,6 @@
+package mp
+
+type Flat struct {
+	A string
+	B int
+}
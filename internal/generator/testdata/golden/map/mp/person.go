@@ -0,0 +1,21 @@
+package mp
+
+import "time"
+
+type Address struct {
+	City  string `json:"city"`
+	Zip   int    `json:"zip,omitempty"`
+	inner int
+}
+
+type Person struct {
+	Name   string   `json:"name"`
+	Age    *int     `json:"age"`
+	Tags   []string `json:"tags"`
+	Home   Address  `json:"home"`
+	Work   *Address `json:"work"`
+	Born   time.Time
+	Secret string `json:"-"`
+}
+
+type Payload map[string]any
@@ -0,0 +1,30 @@
+package mt
+
+import (
+	"errors"
+	"strconv"
+)
+
+type Person struct {
+	First string
+	Last  string
+	Age   int
+}
+
+func (p *Person) FullName() string { return p.First + " " + p.Last }
+func (p Person) Rank() (int64, error) {
+	if p.Age < 0 {
+		return 0, errors.New("negative")
+	}
+	return int64(p.Age), nil
+}
+
+type PersonDTO struct {
+	First    string
+	Last     string
+	FullName string
+	Rank     int32
+	Years    string
+}
+
+func (p *PersonDTO) Age() (int, error) { return strconv.Atoi(p.Years) }
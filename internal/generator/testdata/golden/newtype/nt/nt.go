@@ -0,0 +1,45 @@
+package nt
+
+import "errors"
+
+type UserID struct{ Value string }
+
+type Email struct {
+	Address string
+	checked bool
+}
+
+func NewEmail(a string) (Email, error) {
+	if a == "" {
+		return Email{}, errors.New("empty")
+	}
+	return Email{Address: a, checked: true}, nil
+}
+
+type Tags struct{ Items []string }
+
+type Count struct{ N int32 }
+
+type Node struct {
+	Next *Node
+}
+
+type Domain struct {
+	ID    UserID
+	Owner *UserID
+	Email Email
+	Tags  Tags
+	IDs   []UserID
+	Count Count
+	Node  Node
+}
+
+type Wire struct {
+	ID    string
+	Owner *string
+	Email string
+	Tags  []string
+	IDs   []string
+	Count int64
+	Node  *Node
+}
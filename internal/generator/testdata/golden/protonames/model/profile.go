@@ -0,0 +1,6 @@
+package model
+
+type Profile struct {
+	UID         string
+	DisplayName string
+}
@@ -0,0 +1,9 @@
+package pb
+
+type Profile struct {
+	UserId   string `protobuf:"bytes,1,opt,name=user_id,json=uid,proto3" json:"user_id,omitempty"`
+	FullName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// Deprecated: Marked as deprecated in pn.proto.
+	Legacy string `protobuf:"bytes,3,opt,name=legacy,proto3" json:"legacy,omitempty"`
+	Old    int32  `protobuf:"varint,4,opt,name=old,proto3" json:"old,omitempty"` // Deprecated: Do not use.
+}
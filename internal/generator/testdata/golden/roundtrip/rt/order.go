@@ -0,0 +1,35 @@
+package rt
+
+type Status string
+
+const (
+	StatusNew  Status = "new"
+	StatusDone Status = "done"
+)
+
+type State string
+
+const (
+	StateNew  State = "new"
+	StateDone State = "done"
+)
+
+type Cents int64
+
+func (c Cents) Money() Money { return Money{units: int64(c)} }
+
+func CentsFromMoney(m Money) Cents { return Cents(m.units) }
+
+type Money struct{ units int64 }
+
+type Order struct {
+	ID     string
+	Status Status
+	Price  Cents
+}
+
+type OrderRow struct {
+	ID     string
+	Status State
+	Price  Money
+}
@@ -0,0 +1,21 @@
+package model
+
+import "errors"
+
+type OrderModel struct {
+	ID  string
+	cnt int64
+	n   string
+	p   float64
+}
+
+func (o *OrderModel) SetID(v string) { o.ID = "x" + v }
+func (o *OrderModel) SetCount(v int64) error {
+	if v < 0 {
+		return errors.New("negative")
+	}
+	o.cnt = v
+	return nil
+}
+func (o *OrderModel) SetNote(v string) *OrderModel { o.n = v; return o }
+func (o *OrderModel) Note() string                 { return o.n }
@@ -0,0 +1,12 @@
+package st
+
+type Order struct {
+	ID    string
+	Count int
+	Note  *string
+	Price float64
+}
+
+type Builder struct{ price float64 }
+
+func (o *Order) SetPrice(v float64) { o.Price = v }
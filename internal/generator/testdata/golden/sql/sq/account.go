@@ -0,0 +1,28 @@
+package sq
+
+import "database/sql/driver"
+
+type Status string
+
+func (s Status) Value() (driver.Value, error) { return string(s), nil }
+func (s *Status) Scan(src any) error          { *s = Status(src.(string)); return nil }
+
+type Nullable struct {
+	S     string
+	Valid bool
+}
+
+func (n Nullable) Value() (driver.Value, error) { return n.S, nil }
+func (n *Nullable) Scan(src any) error          { n.S, n.Valid = src.(string); return nil }
+
+type Acc struct {
+	Status Status
+	Name   Nullable
+	Mis    Money
+}
+
+type AccRow struct {
+	Status string
+	Name   string
+	Mis    float64
+}
@@ -0,0 +1,41 @@
+package sq
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+type Email struct{ v string }
+
+func (e Email) Value() (driver.Value, error) { return e.v, nil }
+func (e *Email) Scan(src any) error {
+	s, ok := src.(string)
+	if !ok {
+		return errors.New("bad")
+	}
+	e.v = s
+	return nil
+}
+
+type Money struct{ cents int64 }
+
+func (m Money) Value() (driver.Value, error) { return m.cents, nil }
+func (m *Money) Scan(src any) error          { m.cents, _ = src.(int64); return nil }
+
+type Stamp struct{ t time.Time }
+
+func (m *Stamp) Value() (driver.Value, error) { return m.t, nil }
+func (m *Stamp) Scan(src any) error           { m.t, _ = src.(time.Time); return nil }
+
+type User struct {
+	Email   Email
+	Balance *Money
+	Created Stamp
+}
+
+type Row struct {
+	Email   string
+	Balance int64
+	Created *time.Time
+}
@@ -0,0 +1,23 @@
+package sc
+
+type Level int8
+
+type Form struct {
+	Age    int
+	Score  *float32
+	Active bool
+	Level  Level
+	Count  uint
+	Ids    []int64
+	Other  int
+}
+
+type Query struct {
+	Age    string
+	Score  string
+	Active *string
+	Level  string
+	Count  string
+	Ids    []string
+	Other  string
+}
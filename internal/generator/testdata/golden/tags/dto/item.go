@@ -0,0 +1,8 @@
+package dto
+
+type Item struct {
+	Identifier string `json:"id"`
+	Title      string `json:"name"`
+	Price      int64  `json:"price"`
+	Skip       string `json:"-"`
+}
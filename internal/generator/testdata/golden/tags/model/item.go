@@ -0,0 +1,8 @@
+package model
+
+type Item struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Price int64
+	Skip  string
+}
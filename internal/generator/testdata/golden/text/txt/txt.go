@@ -0,0 +1,37 @@
+package txt
+
+import (
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+type Code struct{ v string }
+
+func (c Code) MarshalText() ([]byte, error)  { return []byte(c.v), nil }
+func (c *Code) UnmarshalText(b []byte) error { c.v = strings.ToUpper(string(b)); return nil }
+
+type Color int
+
+func (c Color) String() string                { return "red" }
+func (c *Color) UnmarshalText(b []byte) error { *c = 1; return nil }
+
+type Model struct {
+	Code   Code
+	Color  Color
+	Amount *big.Int
+	Addr   netip.Prefix
+	IP     netip.Addr
+	Codes  []Code
+	Raw    Code
+}
+
+type DTO struct {
+	Code   string
+	Color  *string
+	Amount string
+	Addr   []byte
+	IP     string
+	Codes  []string
+	Raw    []byte
+}
@@ -0,0 +1,25 @@
+package tp
+
+import "time"
+
+type Event struct {
+	At      time.Time
+	Seen    *time.Time
+	Day     time.Time
+	TTL     time.Duration
+	Timeout time.Duration
+	Born    time.Time
+	Nano    time.Time
+}
+
+type Millis int64
+
+type Row struct {
+	At      Millis
+	Seen    *string
+	Day     string
+	TTL     int32
+	Timeout string
+	Born    int64
+	Nano    int64
+}
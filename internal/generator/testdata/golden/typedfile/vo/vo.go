@@ -0,0 +1,22 @@
+package vo
+
+type Point struct{ x, y int }
+
+func NewPoint(x, y int) Point { return Point{x, y} }
+func (p Point) X() int        { return p.x }
+func (p Point) Y() int        { return p.y }
+
+type PointDTO struct {
+	X int
+	Y int
+}
+
+type Size struct {
+	Width  int
+	Height int
+}
+
+type SizeDTO struct {
+	Width  int64
+	Height int64
+}
@@ -0,0 +1,15 @@
+package domain
+
+type Target interface{ isTarget() }
+
+type UserTarget string
+type GroupTarget int64
+
+func (UserTarget) isTarget()  {}
+func (GroupTarget) isTarget() {}
+
+type Event struct {
+	ID     string
+	Note   string
+	Target Target
+}
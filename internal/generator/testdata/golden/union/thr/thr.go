@@ -0,0 +1,28 @@
+package thr
+
+type Target struct {
+	User  *string `thrift:"user,1" json:"user,omitempty"`
+	Group *int64  `thrift:"group,2" json:"group,omitempty"`
+}
+
+func (p *Target) IsSetUser() bool  { return p.User != nil }
+func (p *Target) IsSetGroup() bool { return p.Group != nil }
+func (p *Target) CountSetFieldsTarget() int {
+	count := 0
+	if p.IsSetUser() {
+		count++
+	}
+	if p.IsSetGroup() {
+		count++
+	}
+	return count
+}
+
+type Event struct {
+	Id     string  `thrift:"id,1"`
+	Note   *string `thrift:"note,2"`
+	Target *Target `thrift:"target,3"`
+}
+
+func (p *Event) IsSetNote() bool   { return p.Note != nil }
+func (p *Event) IsSetTarget() bool { return p.Target != nil }
@@ -0,0 +1,29 @@
+package vo
+
+import (
+	"errors"
+	"time"
+)
+
+type Account struct {
+	email string
+	age   int
+	since time.Time
+}
+
+func NewAccount(email string, age int32, since time.Time) (*Account, error) {
+	if email == "" {
+		return nil, errors.New("empty email")
+	}
+	return &Account{email: email, age: int(age), since: since}, nil
+}
+
+func (a *Account) Email() string   { return a.email }
+func (a *Account) GetAge() int32   { return int32(a.age) }
+func (a Account) Since() time.Time { return a.since }
+
+type AccountDTO struct {
+	Email string
+	Age   int64
+	Since time.Time
+}
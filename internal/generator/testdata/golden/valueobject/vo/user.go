@@ -0,0 +1,28 @@
+package vo
+
+import "errors"
+
+type Email struct{ addr string }
+
+func NewEmail(addr string) (Email, error) {
+	if addr == "" {
+		return Email{}, errors.New("empty email")
+	}
+	return Email{addr: addr}, nil
+}
+
+func (e Email) Addr() string { return e.addr }
+
+func (e Email) Domain() string { return e.addr }
+
+type User struct {
+	Name  string
+	Email Email
+	Alt   *Email
+}
+
+type UserDTO struct {
+	Name  string
+	Email string
+	Alt   *string
+}
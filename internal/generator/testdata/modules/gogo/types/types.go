@@ -0,0 +1,32 @@
+package types
+
+import "time"
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func (*Timestamp) String() string { return "" }
+
+func TimestampProto(t time.Time) (*Timestamp, error) {
+	return &Timestamp{Seconds: t.Unix(), Nanos: int32(t.Nanosecond())}, nil
+}
+func TimestampFromProto(ts *Timestamp) (time.Time, error) {
+	return time.Unix(ts.Seconds, int64(ts.Nanos)), nil
+}
+func TimestampString(ts *Timestamp) string { return "" }
+
+type Duration struct{ Seconds int64 }
+
+func DurationProto(d time.Duration) *Duration              { return &Duration{} }
+func DurationFromProto(p *Duration) (time.Duration, error) { return 0, nil }
+
+type StringValue struct {
+	Value                string
+	XXX_NoUnkeyedLiteral struct{}
+	XXX_sizecache        int32
+}
+
+func (m *StringValue) String() string   { return m.Value }
+func (m *StringValue) GetValue() string { return m.Value }